@@ -24,22 +24,51 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
 	"github.com/kanisterio/kanister/pkg/controller"
+	"github.com/kanisterio/kanister/pkg/eventer"
 	_ "github.com/kanisterio/kanister/pkg/function"
 	"github.com/kanisterio/kanister/pkg/handler"
 	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/leader"
 	"github.com/kanisterio/kanister/pkg/resource"
 )
 
 func main() {
 	ctx := context.Background()
 
-	s := handler.NewServer()
+	// Initialize the clients.
+	log.Infof("Getting kubernetes context")
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("Failed to get k8s config. %+v", err)
+	}
+
+	ns, err := kube.GetControllerNamespace()
+	if err != nil {
+		log.Fatalf("Failed to determine this pod's namespace %+v", err)
+	}
+
+	elector, err := newElector(config, ns)
+	if err != nil {
+		log.Fatalf("Failed to set up leader election. %+v", err)
+	}
+
+	var leadership handler.Leadership
+	if elector != nil {
+		leadership = elector
+	}
+	s := handler.NewServer(leadership)
 	defer func() {
 		if err := s.Shutdown(ctx); err != nil {
 			log.Errorf("Failed to shutdown health check server: %+v", err)
@@ -51,29 +80,44 @@ func main() {
 		}
 	}()
 
-	// Initialize the clients.
-	log.Infof("Getting kubernetes context")
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		log.Fatalf("Failed to get k8s config. %+v", err)
-	}
-
 	// Make sure the CRD's exist.
 	if err := resource.CreateCustomResources(ctx, config); err != nil {
 		log.Fatalf("Failed to create CustomResources. %+v", err)
 	}
 
-	ns, err := kube.GetControllerNamespace()
+	opts, err := controllerOptions()
 	if err != nil {
-		log.Fatalf("Failed to determine this pod's namespace %+v", err)
+		log.Fatalf("Failed to read controller options. %+v", err)
 	}
 
 	// Create and start the watcher.
+	namespaces := watchNamespaces(ns)
 	ctx, cancel := context.WithCancel(ctx)
-	c := controller.New(config)
-	err = c.StartWatch(ctx, ns)
-	if err != nil {
-		log.Fatalf("Failed to start controller. %+v", err)
+	c := controller.NewWithOptions(config, opts)
+	done := make(chan struct{})
+	if elector == nil {
+		err = c.StartWatchNamespaces(ctx, namespaces)
+		if err != nil {
+			log.Fatalf("Failed to start controller. %+v", err)
+		}
+		close(done)
+	} else {
+		go func() {
+			defer close(done)
+			err := elector.Run(ctx, func(ctx context.Context) {
+				if err := c.StartWatchNamespaces(ctx, namespaces); err != nil {
+					log.Fatalf("Failed to start controller. %+v", err)
+				}
+			})
+			if err != nil {
+				log.Fatalf("Failed to run leader election. %+v", err)
+			}
+			// The actions that are executing cannot be handed over, so a
+			// replica that stops leading exits and restarts as a standby.
+			if ctx.Err() == nil {
+				log.Fatalf("Lost leadership, exiting")
+			}
+		}()
 	}
 
 	// create signals to stop watching the resources
@@ -83,6 +127,124 @@ func main() {
 	case <-signalChan:
 		log.Infof("shutdown signal received, exiting...")
 		cancel()
+		// Wait for the leader lock to be released.
+		<-done
 		return
 	}
 }
+
+// newElector returns the leader Elector of the controller if leader election
+// is enabled, and nil otherwise.
+func newElector(config *rest.Config, ns string) (*leader.Elector, error) {
+	v, ok := os.LookupEnv(leaderElectionEnvVar)
+	if !ok || v == "" {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid %s", leaderElectionEnvVar)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	cli, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get a k8s client")
+	}
+	id, err := kube.GetControllerPodName()
+	if err != nil {
+		return nil, err
+	}
+	return leader.NewElector(cli, eventer.NewEventRecorder(cli, "Kanister Controller"), ns, id), nil
+}
+
+const (
+	actionSetTTLEnvVar      = "ACTIONSET_TTL"
+	maxActionSetsEnvVar     = "MAX_ACTIONSETS"
+	archiveActionSetsEnvVar = "ARCHIVE_ACTIONSETS"
+	leaderElectionEnvVar    = "LEADER_ELECTION"
+	recoveryPolicyEnvVar    = "RECOVERY_POLICY"
+	maxActionsEnvVar        = "MAX_CONCURRENT_ACTIONS"
+	maxNSActionsEnvVar      = "MAX_CONCURRENT_ACTIONS_PER_NAMESPACE"
+	watchNamespacesEnvVar   = "WATCH_NAMESPACES"
+	allowListEnvVar         = "CROSS_NAMESPACE_ALLOWLIST"
+)
+
+// controllerOptions reads the options of the controller from the environment.
+func controllerOptions() (controller.Options, error) {
+	var opts controller.Options
+	if v, ok := os.LookupEnv(actionSetTTLEnvVar); ok && v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "Invalid %s", actionSetTTLEnvVar)
+		}
+		opts.ActionSetTTL = ttl
+	}
+	if v, ok := os.LookupEnv(maxActionSetsEnvVar); ok && v != "" {
+		max, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "Invalid %s", maxActionSetsEnvVar)
+		}
+		opts.MaxActionSets = max
+	}
+	if v, ok := os.LookupEnv(archiveActionSetsEnvVar); ok && v != "" {
+		archive, err := strconv.ParseBool(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "Invalid %s", archiveActionSetsEnvVar)
+		}
+		opts.ArchiveActionSets = archive
+	}
+	if v, ok := os.LookupEnv(maxActionsEnvVar); ok && v != "" {
+		max, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "Invalid %s", maxActionsEnvVar)
+		}
+		opts.MaxConcurrentActions = max
+	}
+	if v, ok := os.LookupEnv(maxNSActionsEnvVar); ok && v != "" {
+		max, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, errors.Wrapf(err, "Invalid %s", maxNSActionsEnvVar)
+		}
+		opts.MaxConcurrentActionsPerNamespace = max
+	}
+	opts.CrossNamespaceAllowList = splitList(os.Getenv(allowListEnvVar))
+	switch p := controller.RecoveryPolicy(os.Getenv(recoveryPolicyEnvVar)); p {
+	case "", controller.RecoveryFail, controller.RecoveryRestart:
+		opts.RecoveryPolicy = p
+	default:
+		return opts, errors.Errorf("Invalid %s %q, must be %q or %q", recoveryPolicyEnvVar, p, controller.RecoveryFail, controller.RecoveryRestart)
+	}
+	if opts.ActionSetTTL < 0 || opts.MaxActionSets < 0 {
+		return opts, errors.Errorf("%s and %s must be non-negative", actionSetTTLEnvVar, maxActionSetsEnvVar)
+	}
+	if opts.MaxConcurrentActions < 0 || opts.MaxConcurrentActionsPerNamespace < 0 {
+		return opts, errors.Errorf("%s and %s must be non-negative", maxActionsEnvVar, maxNSActionsEnvVar)
+	}
+	return opts, nil
+}
+
+// watchNamespaces returns the namespaces whose custom resources the
+// controller watches. By default it watches its own namespace. "*" watches all
+// namespaces.
+func watchNamespaces(ns string) []string {
+	v := os.Getenv(watchNamespacesEnvVar)
+	if strings.TrimSpace(v) == "*" {
+		return []string{metav1.NamespaceAll}
+	}
+	if nss := splitList(v); len(nss) > 0 {
+		return nss
+	}
+	return []string{ns}
+}
+
+// splitList splits a comma separated list and drops the empty values.
+func splitList(v string) []string {
+	var l []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			l = append(l, s)
+		}
+	}
+	return l
+}
@@ -2,6 +2,8 @@
 package main
 
 import (
+	// Register the Kanister functions so blueprints can be validated.
+	_ "github.com/kanisterio/kanister/pkg/function"
 	"github.com/kanisterio/kanister/pkg/kanctl"
 )
 
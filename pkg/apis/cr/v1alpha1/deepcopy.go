@@ -5,6 +5,17 @@ package v1alpha1
 func (in *BlueprintPhase) DeepCopyInto(out *BlueprintPhase) {
 	*out = *in
 	// TODO: Handle 'Args'
+	if in.DependsOn != nil {
+		out.DependsOn = make([]string, len(in.DependsOn))
+		copy(out.DependsOn, in.DependsOn)
+	}
+	if in.RetryPolicy != nil {
+		out.RetryPolicy = in.RetryPolicy.DeepCopy()
+	}
+	if in.Timeout != nil {
+		t := *in.Timeout
+		out.Timeout = &t
+	}
 	return
 }
 
@@ -13,5 +24,80 @@ func (in *BlueprintPhase) DeepCopyInto(out *BlueprintPhase) {
 func (in *Phase) DeepCopyInto(out *Phase) {
 	*out = *in
 	// TODO: Handle 'Output' map[string]interface{}
+	if in.StartTime != nil {
+		out.StartTime = in.StartTime.DeepCopy()
+	}
+	if in.EndTime != nil {
+		out.EndTime = in.EndTime.DeepCopy()
+	}
+	if in.DependsOn != nil {
+		out.DependsOn = make([]string, len(in.DependsOn))
+		copy(out.DependsOn, in.DependsOn)
+	}
+	if in.OutputRef != nil {
+		out.OutputRef = in.OutputRef.DeepCopy()
+	}
+	if in.Plan != nil {
+		out.Plan = in.Plan.DeepCopy()
+	}
 	return
 }
+
+// DeepCopyInto handles the PhasePlan deep copies, copying the receiver, writing into out. in must be non-nil.
+// The auto-generated function does not handle the map[string]interface{} args
+func (in *PhasePlan) DeepCopyInto(out *PhasePlan) {
+	*out = *in
+	if in.Args != nil {
+		out.Args = deepCopyJSONValue(in.Args).(map[string]interface{})
+	}
+	if in.Objects != nil {
+		out.Objects = make(map[string]ObjectReference, len(in.Objects))
+		for key, val := range in.Objects {
+			out.Objects[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopyInto handles the Artifact deep copies, copying the receiver, writing into out. in must be non-nil.
+// The auto-generated function does not handle the map[string]interface{} values
+func (in *Artifact) DeepCopyInto(out *Artifact) {
+	*out = *in
+	if in.KeyValue != nil {
+		out.KeyValue = make(map[string]string, len(in.KeyValue))
+		for key, val := range in.KeyValue {
+			out.KeyValue[key] = val
+		}
+	}
+	if in.Values != nil {
+		out.Values = deepCopyJSONValue(in.Values).(map[string]interface{})
+	}
+	if in.Sensitive != nil {
+		out.Sensitive = make([]string, len(in.Sensitive))
+		copy(out.Sensitive, in.Sensitive)
+	}
+	if in.SecretRef != nil {
+		out.SecretRef = in.SecretRef.DeepCopy()
+	}
+	return
+}
+
+// deepCopyJSONValue copies the maps and slices of a value decoded from JSON.
+// Other values, such as strings and numbers, are immutable.
+func deepCopyJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			c[key] = deepCopyJSONValue(val)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			c[i] = deepCopyJSONValue(val)
+		}
+		return c
+	}
+	return v
+}
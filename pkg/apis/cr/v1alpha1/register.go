@@ -52,6 +52,16 @@ var BlueprintResource = opkit.CustomResource{
 	Kind:    reflect.TypeOf(Blueprint{}).Name(),
 }
 
+// BlueprintBindingResource is a CRD for blueprint bindings.
+var BlueprintBindingResource = opkit.CustomResource{
+	Name:    BlueprintBindingResourceName,
+	Plural:  BlueprintBindingResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1beta1.NamespaceScoped,
+	Kind:    reflect.TypeOf(BlueprintBinding{}).Name(),
+}
+
 // ProfileResource is a CRD for blueprints.
 var ProfileResource = opkit.CustomResource{
 	Name:    ProfileResourceName,
@@ -62,6 +72,26 @@ var ProfileResource = opkit.CustomResource{
 	Kind:    reflect.TypeOf(Profile{}).Name(),
 }
 
+// RetentionPolicyResource is a CRD for retention policies.
+var RetentionPolicyResource = opkit.CustomResource{
+	Name:    RetentionPolicyResourceName,
+	Plural:  RetentionPolicyResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1beta1.NamespaceScoped,
+	Kind:    reflect.TypeOf(RetentionPolicy{}).Name(),
+}
+
+// ScheduleResource is a CRD for schedules.
+var ScheduleResource = opkit.CustomResource{
+	Name:    ScheduleResourceName,
+	Plural:  ScheduleResourceNamePlural,
+	Group:   ResourceGroup,
+	Version: SchemeVersion,
+	Scope:   apiextensionsv1beta1.NamespaceScoped,
+	Kind:    reflect.TypeOf(Schedule{}).Name(),
+}
+
 // Kind takes an unqualified kind and returns back a Group qualified GroupKind
 func Kind(kind string) schema.GroupKind {
 	return SchemeGroupVersion.WithKind(kind).GroupKind()
@@ -79,8 +109,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ActionSetList{},
 		&Blueprint{},
 		&BlueprintList{},
+		&BlueprintBinding{},
+		&BlueprintBindingList{},
 		&Profile{},
 		&ProfileList{},
+		&RetentionPolicy{},
+		&RetentionPolicyList{},
+		&Schedule{},
+		&ScheduleList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -20,6 +20,7 @@ which also has the apache 2.0 license.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -41,6 +42,11 @@ const (
 	ActionSetResourceNamePlural = "actionsets"
 )
 
+// ResumeFromAnnotation names a failed or cancelled ActionSet that an
+// ActionSet resumes. Phases that completed in the named ActionSet are not
+// executed again and their output is reused.
+const ResumeFromAnnotation = "kanister.io/resume-from"
+
 var _ runtime.Object = (*ActionSet)(nil)
 
 // +genclient
@@ -77,6 +83,19 @@ type ObjectReference struct {
 // ActionSetSpec is the specification for the actionset.
 type ActionSetSpec struct {
 	Actions []ActionSpec `json:"actions"`
+	// Cancel stops the execution of a pending or running ActionSet. Its
+	// status is kept.
+	Cancel bool `json:"cancel,omitempty"`
+	// TTLSecondsAfterFinished is how long the ActionSet is kept after it
+	// completed, failed or was cancelled. It overrides the default of the
+	// controller.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// Priority orders the ActionSets that are queued because too many
+	// actions are running. ActionSets with a higher priority start first.
+	Priority int32 `json:"priority,omitempty"`
+	// DryRun renders the phases of the actions and records them in the
+	// status of the ActionSet instead of executing them.
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // ActionSpec is the specification for a single Action.
@@ -85,7 +104,10 @@ type ActionSpec struct {
 	Name string `json:"name"`
 	// Object refers to the thing we'll perform this action on.
 	Object ObjectReference `json:"object"`
-	// Blueprint with instructions on how to execute this action.
+	// Blueprint with instructions on how to execute this action. If it is
+	// not set, the controller uses the Blueprint of the BlueprintBinding
+	// that matches the object. A Blueprint in another namespace is referred
+	// to as <namespace>/<name> if the controller allows it.
 	Blueprint string `json:"blueprint,omitempty"`
 	// Artifacts will be passed as inputs into this phase.
 	Artifacts map[string]Artifact `json:"artifacts,omitempty"`
@@ -99,12 +121,41 @@ type ActionSpec struct {
 	// Options will be used to specify additional values
 	// to be used in the Blueprint.
 	Options map[string]string `json:"options"`
+	// Timeout overrides the timeout of the Blueprint action.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // ActionSetStatus is the status for the actionset. This should only be updated by the controller.
 type ActionSetStatus struct {
 	State   State          `json:"state"`
 	Actions []ActionStatus `json:"actions"`
+	// StartTime is when the controller started executing the ActionSet.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is when the ActionSet completed, failed or was cancelled.
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Conditions are the latest observations of the ActionSet's state.
+	Conditions []ActionSetCondition `json:"conditions,omitempty"`
+}
+
+// ActionSetConditionType is the type of an ActionSetCondition.
+type ActionSetConditionType string
+
+const (
+	// ActionSetConditionRunning is true while the ActionSet is executing.
+	ActionSetConditionRunning ActionSetConditionType = "Running"
+	// ActionSetConditionComplete is true once all actions have completed.
+	ActionSetConditionComplete ActionSetConditionType = "Complete"
+	// ActionSetConditionFailed is true if an action failed.
+	ActionSetConditionFailed ActionSetConditionType = "Failed"
+)
+
+// ActionSetCondition describes the state of an ActionSet at a certain point.
+type ActionSetCondition struct {
+	Type               ActionSetConditionType `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
 }
 
 // ActionStatus is updated as we execute phases.
@@ -117,8 +168,16 @@ type ActionStatus struct {
 	Blueprint string `json:"blueprint"`
 	// Phases are sub-actions an are executed sequentially.
 	Phases []Phase `json:"phases"`
+	// DeferPhase is executed after Phases, whether they succeeded or not.
+	DeferPhase *Phase `json:"deferPhase,omitempty"`
 	// Artifacts created by this phase.
 	Artifacts map[string]Artifact `json:"artifacts"`
+	// StartTime is when the action started executing.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is when the action completed, failed or was cancelled.
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// Error is the message of the error that failed the action.
+	Error string `json:"error,omitempty"`
 }
 
 // State is the current state of a phase of execution.
@@ -127,19 +186,54 @@ type State string
 const (
 	// StatePending mean this action or phase has yet to be executed.
 	StatePending State = "pending"
+	// StateQueued means this ActionSet waits until fewer actions are
+	// running before it is executed.
+	StateQueued State = "queued"
 	// StateRunning means this action or phase is currently executing.
 	StateRunning State = "running"
 	// StateFailed means this action or phase was unsuccessful.
 	StateFailed State = "failed"
 	// StateComplete means this action or phase finished successfully.
 	StateComplete State = "complete"
+	// StateTimedOut means this phase did not finish before its timeout or
+	// the timeout of its action.
+	StateTimedOut State = "timedout"
+	// StateSkipped means this phase was not executed because its condition
+	// evaluated to false.
+	StateSkipped State = "skipped"
+	// StateCancelled means this action or phase was stopped before it
+	// finished because its ActionSet was cancelled.
+	StateCancelled State = "cancelled"
 )
 
 // Phase is subcomponent of an action.
 type Phase struct {
-	Name   string                 `json:"name"`
-	State  State                  `json:"state"`
-	Output map[string]interface{} `json:"output"`
+	Name      string                 `json:"name"`
+	State     State                  `json:"state"`
+	Output    map[string]interface{} `json:"output"`
+	Attempts  int                    `json:"attempts,omitempty"`
+	StartTime *metav1.Time           `json:"startTime,omitempty"`
+	EndTime   *metav1.Time           `json:"endTime,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	// DependsOn names the phases of the action that must complete, or be
+	// skipped, before the phase starts.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// OutputRef refers to the Secret that stores the output of the phase
+	// if it contains secret values. Output then holds the output with
+	// those values redacted.
+	OutputRef *ObjectReference `json:"outputRef,omitempty"`
+	// Plan is the rendered phase of a dry-run ActionSet.
+	Plan *PhasePlan `json:"plan,omitempty"`
+}
+
+// PhasePlan is a phase rendered as it would be executed. The secrets in its
+// arguments are redacted.
+type PhasePlan struct {
+	Func    string                     `json:"func"`
+	Args    map[string]interface{}     `json:"args,omitempty"`
+	Objects map[string]ObjectReference `json:"objects,omitempty"`
+	// Skip is true if the condition of the phase is false.
+	Skip bool `json:"skip,omitempty"`
 }
 
 // k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -147,6 +241,20 @@ type Phase struct {
 // Artifact tracks objects produced by an action.
 type Artifact struct {
 	KeyValue map[string]string `json:"keyValue"`
+	// Values holds structured values, such as lists, maps and numbers. In
+	// the output artifacts of a Blueprint, values that are strings are
+	// rendered as templates and the results are decoded as JSON.
+	Values map[string]interface{} `json:"values,omitempty"`
+	// Sensitive lists the keys of KeyValue whose values must not be written
+	// to the status of an ActionSet, such as encryption keys. The controller
+	// stores their rendered values in a Secret owned by the ActionSet and
+	// removes them from KeyValue.
+	Sensitive []string `json:"sensitive,omitempty"`
+	// SecretRef refers to the Secret that holds the values of the sensitive
+	// keys of a rendered artifact. They are stored under
+	// <artifact name>.<key>, so the artifact must keep its name when it is
+	// passed to another ActionSet.
+	SecretRef *ObjectReference `json:"secretRef,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -177,23 +285,77 @@ type Blueprint struct {
 	Actions           map[string]*BlueprintAction `json:"actions"`
 }
 
-// BlueprintAction describes the set of phases that constitute an action.
+// BlueprintAction describes the set of phases that constitute an action. An
+// action may instead use the action of another Blueprint, referenced as
+// <blueprint>/<action>, in which case it inherits its phases and the fields
+// it does not set itself.
 type BlueprintAction struct {
-	Name               string              `json:"name"`
-	Kind               string              `json:"kind"`
-	ConfigMapNames     []string            `json:"configMapNames"`
-	SecretNames        []string            `json:"secretNames"`
-	InputArtifactNames []string            `json:"inputArtifactNames"`
-	OutputArtifacts    map[string]Artifact `json:"outputArtifacts"`
-	Phases             []BlueprintPhase    `json:"phases"`
+	Name               string                `json:"name"`
+	Uses               string                `json:"uses,omitempty"`
+	Kind               string                `json:"kind"`
+	ConfigMapNames     []string              `json:"configMapNames"`
+	SecretNames        []string              `json:"secretNames"`
+	InputArtifactNames []string              `json:"inputArtifactNames"`
+	OutputArtifacts    map[string]Artifact   `json:"outputArtifacts"`
+	Phases             []BlueprintPhase      `json:"phases"`
+	DeferPhase         *BlueprintPhase       `json:"deferPhase,omitempty"`
+	Timeout            *metav1.Duration      `json:"timeout,omitempty"`
+	Parallelism        int                   `json:"parallelism,omitempty"`
+	Options            map[string]OptionSpec `json:"options,omitempty"`
+}
+
+// OptionType is the type of the value of an option.
+type OptionType string
+
+const (
+	// OptionTypeString is the default type of options.
+	OptionTypeString OptionType = "string"
+	// OptionTypeInt options are integers, such as 3.
+	OptionTypeInt OptionType = "int"
+	// OptionTypeBool options are booleans, such as true.
+	OptionTypeBool OptionType = "bool"
+	// OptionTypeDuration options are durations, such as 1h30m.
+	OptionTypeDuration OptionType = "duration"
+)
+
+// OptionSpec declares an option that an ActionSpec passes to a
+// BlueprintAction. Declared options that are not required and not set are
+// set to their default, or to an empty string if they have none.
+type OptionSpec struct {
+	Type        OptionType `json:"type,omitempty"`
+	Default     string     `json:"default,omitempty"`
+	Required    bool       `json:"required,omitempty"`
+	Description string     `json:"description,omitempty"`
 }
 
-// BlueprintPhase is a an individual unit of execution.
+// BlueprintPhase is a an individual unit of execution. A phase that uses the
+// action of another Blueprint, referenced as <blueprint>/<action>, is replaced
+// by the phases of that action when the Blueprint is expanded.
 type BlueprintPhase struct {
-	Func       string                     `json:"func"`
-	Name       string                     `json:"name"`
-	ObjectRefs map[string]ObjectReference `json:"objects"`
-	Args       map[string]interface{}     `json:"args"`
+	Func        string                     `json:"func"`
+	Name        string                     `json:"name"`
+	Uses        string                     `json:"uses,omitempty"`
+	ObjectRefs  map[string]ObjectReference `json:"objects"`
+	Args        map[string]interface{}     `json:"args"`
+	DependsOn   []string                   `json:"dependsOn,omitempty"`
+	If          string                     `json:"if,omitempty"`
+	RetryPolicy *RetryPolicy               `json:"retryPolicy,omitempty"`
+	Timeout     *metav1.Duration           `json:"timeout,omitempty"`
+}
+
+// RetryPolicy describes how a failed phase is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the phase is executed, including
+	// the first attempt. A value of 0 or 1 disables retries.
+	MaxAttempts int `json:"maxAttempts"`
+	// MinBackoff is the wait before the first retry. Defaults to 100ms.
+	MinBackoff *metav1.Duration `json:"minBackoff,omitempty"`
+	// MaxBackoff caps the exponentially increasing wait between retries.
+	// Defaults to 10s.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+	// RetryableErrors is a list of regular expressions matched against the
+	// error returned by the phase. If empty, every error is retried.
+	RetryableErrors []string `json:"retryableErrors,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -205,6 +367,53 @@ type BlueprintList struct {
 	Items           []*Blueprint `json:"items"`
 }
 
+// These names are used to query BlueprintBinding API objects.
+const (
+	BlueprintBindingResourceName       = "blueprintbinding"
+	BlueprintBindingResourceNamePlural = "blueprintbindings"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BlueprintBinding selects the Blueprint of actions that do not name one.
+type BlueprintBinding struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              *BlueprintBindingSpec `json:"spec"`
+}
+
+// BlueprintBindingSpec is the specification for the BlueprintBinding. An
+// object matches the binding if it matches all of the criteria that are set.
+type BlueprintBindingSpec struct {
+	// Blueprint is the name of the Blueprint, in the namespace of the
+	// binding, that is used for the objects that match.
+	Blueprint string `json:"blueprint"`
+	// Priority decides which of the bindings that match an object is used.
+	// The binding with the highest priority wins.
+	Priority int `json:"priority,omitempty"`
+	// Kinds match the kind of the object, such as statefulset, or the
+	// resource of generic objects, such as configmaps.
+	Kinds []string `json:"kinds,omitempty"`
+	// Namespaces match the namespace of the object.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selector matches the labels of the object.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Annotations match objects that have all of these annotations. An
+	// empty value matches any value.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BlueprintBindingList is the definition of a list of BlueprintBindings
+type BlueprintBindingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []*BlueprintBinding `json:"items"`
+}
+
 // These names are used to query Profile API objects.
 const (
 	ProfileResourceName       = "profile"
@@ -270,3 +479,188 @@ type ProfileList struct {
 	metav1.ListMeta `json:"metadata"`
 	Items           []*Profile `json:"items"`
 }
+
+// These names are used to query Schedule API objects.
+const (
+	ScheduleResourceName       = "schedule"
+	ScheduleResourceNamePlural = "schedules"
+)
+
+// ScheduleLabel is set on the ActionSets that a Schedule creates to the name
+// of the Schedule.
+const ScheduleLabel = "kanister.io/schedule"
+
+// These labels are set on the pods that the phases of an ActionSet create to
+// the namespace, name and UID of the ActionSet. The name is omitted if it is
+// not a valid label value.
+const (
+	ActionSetNamespaceLabel = "kanister.io/actionset-namespace"
+	ActionSetNameLabel      = "kanister.io/actionset-name"
+	ActionSetUIDLabel       = "kanister.io/actionset-uid"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Schedule creates ActionSets from a template on a cron schedule.
+type Schedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              *ScheduleSpec   `json:"spec"`
+	Status            *ScheduleStatus `json:"status,omitempty"`
+}
+
+// ScheduleSpec is the specification for the Schedule.
+type ScheduleSpec struct {
+	// Cron is a standard five field cron expression, such as "0 2 * * *", or
+	// a descriptor such as "@daily". It is evaluated in UTC.
+	Cron string `json:"cron"`
+	// ActionSetTemplate is the spec of the ActionSets that are created.
+	ActionSetTemplate *ActionSetSpec `json:"actionSetTemplate"`
+	// ConcurrencyPolicy decides what happens when a run is due while an
+	// ActionSet created by an earlier run is still pending or running.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// HistoryLimit is the number of finished ActionSets that are kept. Older
+	// ones are deleted. Zero keeps all of them.
+	HistoryLimit int `json:"historyLimit,omitempty"`
+	// MissedRunPolicy decides what happens to runs that were missed, for
+	// example while the controller was down.
+	MissedRunPolicy MissedRunPolicy `json:"missedRunPolicy,omitempty"`
+}
+
+// ConcurrencyPolicy describes how a Schedule treats concurrent runs.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow creates the ActionSet regardless of earlier
+	// runs. It is the default.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "allow"
+	// ConcurrencyPolicyForbid skips the run while an earlier run is active.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "forbid"
+	// ConcurrencyPolicyReplace cancels the active runs and creates the
+	// ActionSet.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "replace"
+)
+
+// MissedRunPolicy describes how a Schedule treats runs whose time passed
+// without them being started.
+type MissedRunPolicy string
+
+const (
+	// MissedRunPolicyRunOnce starts a single run for the most recent missed
+	// time, however many runs were missed. It is the default.
+	MissedRunPolicyRunOnce MissedRunPolicy = "runOnce"
+	// MissedRunPolicySkip skips missed runs and waits for the next one.
+	MissedRunPolicySkip MissedRunPolicy = "skip"
+)
+
+// ScheduleStatus is the status of the Schedule.
+type ScheduleStatus struct {
+	// LastScheduleTime is the time of the last run that was due, whether it
+	// was started or skipped.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// LastActionSet is the name of the ActionSet created by the last run
+	// that was started.
+	LastActionSet string `json:"lastActionSet,omitempty"`
+	// LastRunState is the state of LastActionSet.
+	LastRunState State `json:"lastRunState,omitempty"`
+	// Active lists the ActionSets created by the Schedule that are pending
+	// or running.
+	Active []string `json:"active,omitempty"`
+	// Error describes why the Schedule could not be run, if it could not.
+	Error string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ScheduleList is the definition of a list of Schedules
+type ScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []*Schedule `json:"items"`
+}
+
+// These names are used to query RetentionPolicy API objects.
+const (
+	RetentionPolicyResourceName       = "retentionpolicy"
+	RetentionPolicyResourceNamePlural = "retentionpolicies"
+)
+
+const (
+	// RetentionPolicyLabel is set on the ActionSets that a RetentionPolicy
+	// creates to delete expired backups to the name of the RetentionPolicy.
+	RetentionPolicyLabel = "kanister.io/retention-policy"
+	// ExpiredActionSetAnnotation names the expired ActionSet whose
+	// artifacts an ActionSet created by a RetentionPolicy deletes.
+	ExpiredActionSetAnnotation = "kanister.io/expired-actionset"
+	// DefaultDeleteAction is the action that a RetentionPolicy runs to
+	// delete the artifacts of expired backups by default.
+	DefaultDeleteAction = "delete"
+)
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RetentionPolicy deletes the artifacts of backup ActionSets that are no
+// longer retained and then the ActionSets themselves.
+type RetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              *RetentionPolicySpec   `json:"spec"`
+	Status            *RetentionPolicyStatus `json:"status,omitempty"`
+}
+
+// RetentionPolicySpec is the specification for the RetentionPolicy. Backups
+// are grouped by the object and the blueprint of their actions. Within a
+// group, a backup is retained if any of the keep rules matches it, or if none
+// are set, and it is not older than MaxAge.
+type RetentionPolicySpec struct {
+	// Selector selects the ActionSets in the namespace of the policy that
+	// it applies to. It applies to all of them if it is nil.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Action is the name of the action that creates the backups. Only
+	// completed ActionSets whose actions all have this name are retained.
+	Action string `json:"action"`
+	// DeleteAction is the name of the blueprint action that deletes the
+	// artifacts of a backup. Defaults to DefaultDeleteAction.
+	DeleteAction string `json:"deleteAction,omitempty"`
+	// KeepLast retains the most recent backups.
+	KeepLast int `json:"keepLast,omitempty"`
+	// KeepDaily retains the most recent backup of each of the most recent
+	// days that have one.
+	KeepDaily int `json:"keepDaily,omitempty"`
+	// KeepWeekly retains the most recent backup of each of the most recent
+	// ISO weeks that have one.
+	KeepWeekly int `json:"keepWeekly,omitempty"`
+	// KeepMonthly retains the most recent backup of each of the most recent
+	// months that have one.
+	KeepMonthly int `json:"keepMonthly,omitempty"`
+	// MaxAge expires backups that completed longer ago, even if a keep rule
+	// matches them.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// RetentionPolicyStatus is the status of the RetentionPolicy.
+type RetentionPolicyStatus struct {
+	// Expiring lists the expired ActionSets whose artifacts are being
+	// deleted.
+	Expiring []string `json:"expiring,omitempty"`
+	// FailedDeletes lists the ActionSets created to delete the artifacts of
+	// expired ActionSets that failed. The expired ActionSets are kept until
+	// the failed ActionSets are deleted, when their deletion is retried.
+	FailedDeletes []string `json:"failedDeletes,omitempty"`
+	// Error describes why the RetentionPolicy could not be applied, if it
+	// could not.
+	Error string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RetentionPolicyList is the definition of a list of RetentionPolicies
+type RetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []*RetentionPolicy `json:"items"`
+}
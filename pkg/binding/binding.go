@@ -0,0 +1,150 @@
+package binding
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Resolve returns the name of the Blueprint that the BlueprintBindings in
+// the namespace bind to the object.
+func Resolve(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, namespace string, obj crv1alpha1.ObjectReference) (string, error) {
+	bbl, err := crCli.CrV1alpha1().BlueprintBindings(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to list BlueprintBindings")
+	}
+	var om metav1.Object
+	for _, bb := range bbl.Items {
+		if bb.Spec != nil && (bb.Spec.Selector != nil || len(bb.Spec.Annotations) > 0) {
+			// Labels and annotations are only fetched if a binding needs them.
+			if om, err = objectMeta(ctx, cli, obj); err != nil {
+				return "", err
+			}
+			break
+		}
+	}
+	bb, err := Match(bbl.Items, obj, om)
+	if err != nil {
+		return "", err
+	}
+	if bb == nil {
+		return "", errors.Errorf("No BlueprintBinding matches %s %s/%s", objectKind(obj), obj.Namespace, obj.Name)
+	}
+	return bb.Spec.Blueprint, nil
+}
+
+// Match returns the binding with the highest priority that matches the
+// object, or nil if none does. om holds the labels and annotations of the
+// object. It may be nil if none of the bindings match them.
+func Match(bbs []*crv1alpha1.BlueprintBinding, obj crv1alpha1.ObjectReference, om metav1.Object) (*crv1alpha1.BlueprintBinding, error) {
+	var match *crv1alpha1.BlueprintBinding
+	var tied bool
+	for _, bb := range bbs {
+		ok, err := matches(bb, obj, om)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid BlueprintBinding %s", bb.GetName())
+		}
+		if !ok {
+			continue
+		}
+		switch {
+		case match == nil || bb.Spec.Priority > match.Spec.Priority:
+			match, tied = bb, false
+		case bb.Spec.Priority == match.Spec.Priority:
+			tied = true
+		}
+	}
+	if tied {
+		return nil, errors.Errorf("Several BlueprintBindings with priority %d match %s %s/%s", match.Spec.Priority, objectKind(obj), obj.Namespace, obj.Name)
+	}
+	return match, nil
+}
+
+func matches(bb *crv1alpha1.BlueprintBinding, obj crv1alpha1.ObjectReference, om metav1.Object) (bool, error) {
+	if bb.Spec == nil {
+		return false, nil
+	}
+	if len(bb.Spec.Kinds) > 0 && !containsFold(bb.Spec.Kinds, objectKind(obj)) {
+		return false, nil
+	}
+	if len(bb.Spec.Namespaces) > 0 && !containsFold(bb.Spec.Namespaces, obj.Namespace) {
+		return false, nil
+	}
+	if bb.Spec.Selector != nil {
+		sel, err := metav1.LabelSelectorAsSelector(bb.Spec.Selector)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if om == nil || !sel.Matches(labels.Set(om.GetLabels())) {
+			return false, nil
+		}
+	}
+	for k, v := range bb.Spec.Annotations {
+		if om == nil {
+			return false, nil
+		}
+		if av, ok := om.GetAnnotations()[k]; !ok || (v != "" && v != av) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// objectKind returns the kind of the object, or its resource if it is a
+// generic object.
+func objectKind(obj crv1alpha1.ObjectReference) string {
+	if obj.Kind != "" {
+		return strings.ToLower(obj.Kind)
+	}
+	return obj.Resource
+}
+
+func containsFold(l []string, s string) bool {
+	for _, e := range l {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func objectMeta(ctx context.Context, cli kubernetes.Interface, obj crv1alpha1.ObjectReference) (metav1.Object, error) {
+	var om metav1.Object
+	var err error
+	switch strings.ToLower(obj.Kind) {
+	case param.DeploymentKind:
+		om, err = cli.AppsV1().Deployments(obj.Namespace).Get(obj.Name, metav1.GetOptions{})
+	case param.StatefulSetKind:
+		om, err = cli.AppsV1().StatefulSets(obj.Namespace).Get(obj.Name, metav1.GetOptions{})
+	case param.PVCKind:
+		om, err = cli.CoreV1().PersistentVolumeClaims(obj.Namespace).Get(obj.Name, metav1.GetOptions{})
+	case param.NamespaceKind:
+		om, err = cli.CoreV1().Namespaces().Get(obj.Name, metav1.GetOptions{})
+	default:
+		gvr := schema.GroupVersionResource{
+			Group:    obj.Group,
+			Version:  obj.APIVersion,
+			Resource: obj.Resource,
+		}
+		u, ferr := kube.FetchUnstructuredObject(gvr, obj.Namespace, obj.Name)
+		if ferr != nil {
+			return nil, errors.Wrapf(ferr, "Failed to fetch %s %s/%s", obj.Resource, obj.Namespace, obj.Name)
+		}
+		om, err = meta.Accessor(u)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to fetch %s %s/%s", objectKind(obj), obj.Namespace, obj.Name)
+	}
+	return om, nil
+}
@@ -0,0 +1,156 @@
+package binding
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type BindingSuite struct{}
+
+var _ = Suite(&BindingSuite{})
+
+func (s *BindingSuite) TestMatch(c *C) {
+	newBinding := func(name string, priority int, spec crv1alpha1.BlueprintBindingSpec) *crv1alpha1.BlueprintBinding {
+		spec.Blueprint = name + "-blueprint"
+		spec.Priority = priority
+		return &crv1alpha1.BlueprintBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       &spec,
+		}
+	}
+	mysql := crv1alpha1.ObjectReference{Kind: param.StatefulSetKind, Namespace: "mysql", Name: "mysql"}
+	mysqlMeta := &metav1.ObjectMeta{
+		Labels:      map[string]string{"app": "mysql"},
+		Annotations: map[string]string{"kanister.io/backup": "true"},
+	}
+	cm := crv1alpha1.ObjectReference{APIVersion: "v1", Resource: "configmaps", Namespace: "mysql", Name: "config"}
+	for _, tc := range []struct {
+		bbs     []*crv1alpha1.BlueprintBinding
+		obj     crv1alpha1.ObjectReference
+		om      metav1.Object
+		match   string
+		checker Checker
+	}{
+		{
+			bbs:     nil,
+			obj:     mysql,
+			match:   "",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("statefulsets", 0, crv1alpha1.BlueprintBindingSpec{Kinds: []string{"StatefulSet"}}),
+				newBinding("deployments", 0, crv1alpha1.BlueprintBindingSpec{Kinds: []string{"deployment"}}),
+			},
+			obj:     mysql,
+			match:   "statefulsets",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("configmaps", 0, crv1alpha1.BlueprintBindingSpec{Kinds: []string{"configmaps"}}),
+			},
+			obj:     cm,
+			match:   "configmaps",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("default", 0, crv1alpha1.BlueprintBindingSpec{}),
+				newBinding("mysql-ns", 1, crv1alpha1.BlueprintBindingSpec{Namespaces: []string{"mysql"}}),
+				newBinding("other-ns", 2, crv1alpha1.BlueprintBindingSpec{Namespaces: []string{"other"}}),
+			},
+			obj:     mysql,
+			match:   "mysql-ns",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("labeled", 1, crv1alpha1.BlueprintBindingSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "mysql"}},
+				}),
+				newBinding("annotated", 2, crv1alpha1.BlueprintBindingSpec{
+					Annotations: map[string]string{"kanister.io/backup": ""},
+				}),
+			},
+			obj:     mysql,
+			om:      mysqlMeta,
+			match:   "annotated",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("labeled", 1, crv1alpha1.BlueprintBindingSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "postgres"}},
+				}),
+				newBinding("annotated", 2, crv1alpha1.BlueprintBindingSpec{
+					Annotations: map[string]string{"kanister.io/backup": "false"},
+				}),
+			},
+			obj:     mysql,
+			om:      mysqlMeta,
+			match:   "",
+			checker: IsNil,
+		},
+		{
+			// Bindings on labels do not match without them.
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("labeled", 1, crv1alpha1.BlueprintBindingSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "mysql"}},
+				}),
+			},
+			obj:     mysql,
+			match:   "",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("first", 1, crv1alpha1.BlueprintBindingSpec{}),
+				newBinding("second", 1, crv1alpha1.BlueprintBindingSpec{}),
+			},
+			obj:     mysql,
+			checker: NotNil,
+		},
+		{
+			// A tie is broken by a binding with a higher priority.
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("first", 1, crv1alpha1.BlueprintBindingSpec{}),
+				newBinding("second", 1, crv1alpha1.BlueprintBindingSpec{}),
+				newBinding("third", 2, crv1alpha1.BlueprintBindingSpec{}),
+			},
+			obj:     mysql,
+			match:   "third",
+			checker: IsNil,
+		},
+		{
+			bbs: []*crv1alpha1.BlueprintBinding{
+				newBinding("invalid", 1, crv1alpha1.BlueprintBindingSpec{
+					Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}}},
+				}),
+			},
+			obj:     mysql,
+			om:      mysqlMeta,
+			checker: NotNil,
+		},
+	} {
+		bb, err := Match(tc.bbs, tc.obj, tc.om)
+		c.Check(err, tc.checker)
+		if err != nil {
+			continue
+		}
+		if tc.match == "" {
+			c.Check(bb, IsNil)
+		} else {
+			c.Assert(bb, NotNil)
+			c.Check(bb.Spec.Blueprint, Equals, tc.match+"-blueprint")
+		}
+	}
+}
@@ -24,8 +24,17 @@ func New() Getter {
 	return &getter{}
 }
 
-// Get returns a provider for the requested storage type in the specified region
+// Get returns a provider for the requested storage type in the specified
+// region. The latency and the errors of its calls are recorded as metrics.
 func (*getter) Get(storageType blockstorage.Type, config map[string]string) (blockstorage.Provider, error) {
+	p, err := get(storageType, config)
+	if err != nil {
+		return nil, err
+	}
+	return blockstorage.WithMetrics(p), nil
+}
+
+func get(storageType blockstorage.Type, config map[string]string) (blockstorage.Provider, error) {
 	switch storageType {
 	case blockstorage.TypeEBS:
 		return awsebs.NewProvider(config)
@@ -0,0 +1,111 @@
+package blockstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/kanisterio/kanister/pkg/metrics"
+)
+
+// WithMetrics returns a Provider that records the latency and the errors of
+// the calls to p.
+func WithMetrics(p Provider) Provider {
+	if p == nil {
+		return nil
+	}
+	return &metricsProvider{Provider: p}
+}
+
+var _ Provider = (*metricsProvider)(nil)
+
+type metricsProvider struct {
+	Provider
+}
+
+func (p *metricsProvider) observe(operation string, start time.Time, err error) {
+	metrics.ObserveStorage(metrics.StoreBlockStorage, string(p.Type()), operation, start, err)
+}
+
+func (p *metricsProvider) VolumeCreate(ctx context.Context, volume Volume) (*Volume, error) {
+	start := time.Now()
+	v, err := p.Provider.VolumeCreate(ctx, volume)
+	p.observe("VolumeCreate", start, err)
+	return v, err
+}
+
+func (p *metricsProvider) VolumeCreateFromSnapshot(ctx context.Context, snapshot Snapshot, tags map[string]string) (*Volume, error) {
+	start := time.Now()
+	v, err := p.Provider.VolumeCreateFromSnapshot(ctx, snapshot, tags)
+	p.observe("VolumeCreateFromSnapshot", start, err)
+	return v, err
+}
+
+func (p *metricsProvider) VolumeDelete(ctx context.Context, volume *Volume) error {
+	start := time.Now()
+	err := p.Provider.VolumeDelete(ctx, volume)
+	p.observe("VolumeDelete", start, err)
+	return err
+}
+
+func (p *metricsProvider) VolumeGet(ctx context.Context, id string, zone string) (*Volume, error) {
+	start := time.Now()
+	v, err := p.Provider.VolumeGet(ctx, id, zone)
+	p.observe("VolumeGet", start, err)
+	return v, err
+}
+
+func (p *metricsProvider) SnapshotCopy(ctx context.Context, from Snapshot, to Snapshot) (*Snapshot, error) {
+	start := time.Now()
+	s, err := p.Provider.SnapshotCopy(ctx, from, to)
+	p.observe("SnapshotCopy", start, err)
+	return s, err
+}
+
+func (p *metricsProvider) SnapshotCreate(ctx context.Context, volume Volume, tags map[string]string) (*Snapshot, error) {
+	start := time.Now()
+	s, err := p.Provider.SnapshotCreate(ctx, volume, tags)
+	p.observe("SnapshotCreate", start, err)
+	return s, err
+}
+
+func (p *metricsProvider) SnapshotCreateWaitForCompletion(ctx context.Context, snapshot *Snapshot) error {
+	start := time.Now()
+	err := p.Provider.SnapshotCreateWaitForCompletion(ctx, snapshot)
+	p.observe("SnapshotCreateWaitForCompletion", start, err)
+	return err
+}
+
+func (p *metricsProvider) SnapshotDelete(ctx context.Context, snapshot *Snapshot) error {
+	start := time.Now()
+	err := p.Provider.SnapshotDelete(ctx, snapshot)
+	p.observe("SnapshotDelete", start, err)
+	return err
+}
+
+func (p *metricsProvider) SnapshotGet(ctx context.Context, id string) (*Snapshot, error) {
+	start := time.Now()
+	s, err := p.Provider.SnapshotGet(ctx, id)
+	p.observe("SnapshotGet", start, err)
+	return s, err
+}
+
+func (p *metricsProvider) SetTags(ctx context.Context, resource interface{}, tags map[string]string) error {
+	start := time.Now()
+	err := p.Provider.SetTags(ctx, resource, tags)
+	p.observe("SetTags", start, err)
+	return err
+}
+
+func (p *metricsProvider) VolumesList(ctx context.Context, tags map[string]string, zone string) ([]*Volume, error) {
+	start := time.Now()
+	vs, err := p.Provider.VolumesList(ctx, tags, zone)
+	p.observe("VolumesList", start, err)
+	return vs, err
+}
+
+func (p *metricsProvider) SnapshotsList(ctx context.Context, tags map[string]string) ([]*Snapshot, error) {
+	start := time.Now()
+	ss, err := p.Provider.SnapshotsList(ctx, tags)
+	p.observe("SnapshotsList", start, err)
+	return ss, err
+}
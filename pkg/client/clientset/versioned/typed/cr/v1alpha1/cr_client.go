@@ -29,7 +29,10 @@ type CrV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ActionSetsGetter
 	BlueprintsGetter
+	BlueprintBindingsGetter
 	ProfilesGetter
+	RetentionPoliciesGetter
+	SchedulesGetter
 }
 
 // CrV1alpha1Client is used to interact with features provided by the cr group.
@@ -45,10 +48,22 @@ func (c *CrV1alpha1Client) Blueprints(namespace string) BlueprintInterface {
 	return newBlueprints(c, namespace)
 }
 
+func (c *CrV1alpha1Client) BlueprintBindings(namespace string) BlueprintBindingInterface {
+	return newBlueprintBindings(c, namespace)
+}
+
 func (c *CrV1alpha1Client) Profiles(namespace string) ProfileInterface {
 	return newProfiles(c, namespace)
 }
 
+func (c *CrV1alpha1Client) RetentionPolicies(namespace string) RetentionPolicyInterface {
+	return newRetentionPolicies(c, namespace)
+}
+
+func (c *CrV1alpha1Client) Schedules(namespace string) ScheduleInterface {
+	return newSchedules(c, namespace)
+}
+
 // NewForConfig creates a new CrV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*CrV1alpha1Client, error) {
 	config := *c
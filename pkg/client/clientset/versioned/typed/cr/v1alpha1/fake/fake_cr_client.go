@@ -36,10 +36,22 @@ func (c *FakeCrV1alpha1) Blueprints(namespace string) v1alpha1.BlueprintInterfac
 	return &FakeBlueprints{c, namespace}
 }
 
+func (c *FakeCrV1alpha1) BlueprintBindings(namespace string) v1alpha1.BlueprintBindingInterface {
+	return &FakeBlueprintBindings{c, namespace}
+}
+
 func (c *FakeCrV1alpha1) Profiles(namespace string) v1alpha1.ProfileInterface {
 	return &FakeProfiles{c, namespace}
 }
 
+func (c *FakeCrV1alpha1) RetentionPolicies(namespace string) v1alpha1.RetentionPolicyInterface {
+	return &FakeRetentionPolicies{c, namespace}
+}
+
+func (c *FakeCrV1alpha1) Schedules(namespace string) v1alpha1.ScheduleInterface {
+	return &FakeSchedules{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeCrV1alpha1) RESTClient() rest.Interface {
@@ -22,4 +22,10 @@ type ActionSetExpansion interface{}
 
 type BlueprintExpansion interface{}
 
+type BlueprintBindingExpansion interface{}
+
 type ProfileExpansion interface{}
+
+type RetentionPolicyExpansion interface{}
+
+type ScheduleExpansion interface{}
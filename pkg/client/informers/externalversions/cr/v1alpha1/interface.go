@@ -28,8 +28,14 @@ type Interface interface {
 	ActionSets() ActionSetInformer
 	// Blueprints returns a BlueprintInformer.
 	Blueprints() BlueprintInformer
+	// BlueprintBindings returns a BlueprintBindingInformer.
+	BlueprintBindings() BlueprintBindingInformer
 	// Profiles returns a ProfileInformer.
 	Profiles() ProfileInformer
+	// RetentionPolicies returns a RetentionPolicyInformer.
+	RetentionPolicies() RetentionPolicyInformer
+	// Schedules returns a ScheduleInformer.
+	Schedules() ScheduleInformer
 }
 
 type version struct {
@@ -53,7 +59,22 @@ func (v *version) Blueprints() BlueprintInformer {
 	return &blueprintInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BlueprintBindings returns a BlueprintBindingInformer.
+func (v *version) BlueprintBindings() BlueprintBindingInformer {
+	return &blueprintBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Profiles returns a ProfileInformer.
 func (v *version) Profiles() ProfileInformer {
 	return &profileInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RetentionPolicies returns a RetentionPolicyInformer.
+func (v *version) RetentionPolicies() RetentionPolicyInformer {
+	return &retentionPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Schedules returns a ScheduleInformer.
+func (v *version) Schedules() ScheduleInformer {
+	return &scheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -57,8 +57,14 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().ActionSets().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("blueprints"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Blueprints().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("blueprintbindings"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().BlueprintBindings().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("profiles"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Profiles().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("retentionpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().RetentionPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("schedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Cr().V1alpha1().Schedules().Informer()}, nil
 
 	}
 
@@ -34,6 +34,14 @@ type BlueprintListerExpansion interface{}
 // BlueprintNamespaceLister.
 type BlueprintNamespaceListerExpansion interface{}
 
+// BlueprintBindingListerExpansion allows custom methods to be added to
+// BlueprintBindingLister.
+type BlueprintBindingListerExpansion interface{}
+
+// BlueprintBindingNamespaceListerExpansion allows custom methods to be added to
+// BlueprintBindingNamespaceLister.
+type BlueprintBindingNamespaceListerExpansion interface{}
+
 // ProfileListerExpansion allows custom methods to be added to
 // ProfileLister.
 type ProfileListerExpansion interface{}
@@ -41,3 +49,19 @@ type ProfileListerExpansion interface{}
 // ProfileNamespaceListerExpansion allows custom methods to be added to
 // ProfileNamespaceLister.
 type ProfileNamespaceListerExpansion interface{}
+
+// RetentionPolicyListerExpansion allows custom methods to be added to
+// RetentionPolicyLister.
+type RetentionPolicyListerExpansion interface{}
+
+// RetentionPolicyNamespaceListerExpansion allows custom methods to be added to
+// RetentionPolicyNamespaceLister.
+type RetentionPolicyNamespaceListerExpansion interface{}
+
+// ScheduleListerExpansion allows custom methods to be added to
+// ScheduleLister.
+type ScheduleListerExpansion interface{}
+
+// ScheduleNamespaceListerExpansion allows custom methods to be added to
+// ScheduleNamespaceLister.
+type ScheduleNamespaceListerExpansion interface{}
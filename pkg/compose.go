@@ -0,0 +1,285 @@
+package kanister
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+const (
+	// usesSeparator separates the Blueprint and the action in a reference
+	// to the action of another Blueprint.
+	usesSeparator = "/"
+	// phaseNameSeparator joins the name of a phase that uses another action
+	// and the names of the phases of that action. It is valid in template
+	// field names, so the phases can be referred to as .Phases.<phase>_<name>.
+	phaseNameSeparator = "_"
+)
+
+// BlueprintGetter returns the Blueprint with the given name.
+type BlueprintGetter func(name string) (*crv1alpha1.Blueprint, error)
+
+// ExpandBlueprint returns a copy of the Blueprint in which the actions that
+// use another action inherit its phases, its defer phase and the fields they
+// do not set themselves, and in which the phases that use another action are
+// replaced by the phases of that action.
+//
+// The imported phases are named <phase>_<name>, and their dependencies and
+// the references of their templates to each other are renamed accordingly.
+// Imported phases that do not depend on other imported phases depend on the
+// dependencies of the phase that uses the action, and phases that depend on
+// it depend on all of the imported phases. The Blueprints that are used,
+// other than the Blueprint itself, are fetched with get.
+func ExpandBlueprint(bp *crv1alpha1.Blueprint, get BlueprintGetter) (*crv1alpha1.Blueprint, error) {
+	ebp := bp.DeepCopy()
+	if !BlueprintUses(bp) {
+		return ebp, nil
+	}
+	e := &expander{
+		get:        get,
+		blueprints: map[string]*crv1alpha1.Blueprint{bp.GetName(): bp},
+	}
+	names := make([]string, 0, len(bp.Actions))
+	for name := range bp.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if bp.Actions[name] == nil {
+			continue
+		}
+		a, err := e.action(bp, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to expand action %s", name)
+		}
+		ebp.Actions[name] = a
+	}
+	return ebp, nil
+}
+
+// BlueprintUses returns whether an action or a phase of the Blueprint uses
+// the action of another Blueprint.
+func BlueprintUses(bp *crv1alpha1.Blueprint) bool {
+	for _, a := range bp.Actions {
+		if a != nil && actionUses(*a) {
+			return true
+		}
+	}
+	return false
+}
+
+func actionUses(a crv1alpha1.BlueprintAction) bool {
+	if a.Uses != "" || (a.DeferPhase != nil && a.DeferPhase.Uses != "") {
+		return true
+	}
+	return phasesUse(a.Phases)
+}
+
+func phasesUse(phases []crv1alpha1.BlueprintPhase) bool {
+	for _, p := range phases {
+		if p.Uses != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// expander expands the actions of Blueprints. It keeps track of the actions
+// that are being expanded to detect actions that use each other in a cycle.
+type expander struct {
+	get        BlueprintGetter
+	blueprints map[string]*crv1alpha1.Blueprint
+	stack      []string
+}
+
+func (e *expander) action(bp *crv1alpha1.Blueprint, name string) (*crv1alpha1.BlueprintAction, error) {
+	ref := bp.GetName() + usesSeparator + name
+	for i, r := range e.stack {
+		if r == ref {
+			return nil, errors.Errorf("Actions use each other in a cycle: %s", strings.Join(append(e.stack[i:], ref), " -> "))
+		}
+	}
+	e.stack = append(e.stack, ref)
+	defer func() { e.stack = e.stack[:len(e.stack)-1] }()
+
+	a, ok := bp.Actions[name]
+	if !ok || a == nil {
+		return nil, errors.Errorf("Action %s not found in blueprint %s", name, bp.GetName())
+	}
+	if a.DeferPhase != nil && a.DeferPhase.Uses != "" {
+		return nil, errors.Errorf("Defer phase %s of action %s must not use another action", a.DeferPhase.Name, ref)
+	}
+	if a.Uses != "" {
+		return e.inherit(ref, a)
+	}
+	ea := a.DeepCopy()
+	if phasesUse(a.Phases) {
+		phases, err := e.phases(ref, ea.Phases)
+		if err != nil {
+			return nil, err
+		}
+		ea.Phases = phases
+	}
+	return ea, nil
+}
+
+// inherit returns the action that a uses, with the fields that a sets.
+func (e *expander) inherit(ref string, a *crv1alpha1.BlueprintAction) (*crv1alpha1.BlueprintAction, error) {
+	if len(a.Phases) > 0 || a.DeferPhase != nil {
+		return nil, errors.Errorf("Action %s uses %s and must not define phases", ref, a.Uses)
+	}
+	ea, err := e.uses(a.Uses)
+	if err != nil {
+		return nil, err
+	}
+	ea.Name = a.Name
+	if a.Kind != "" {
+		ea.Kind = a.Kind
+	}
+	if a.ConfigMapNames != nil {
+		ea.ConfigMapNames = append([]string(nil), a.ConfigMapNames...)
+	}
+	if a.SecretNames != nil {
+		ea.SecretNames = append([]string(nil), a.SecretNames...)
+	}
+	if a.InputArtifactNames != nil {
+		ea.InputArtifactNames = append([]string(nil), a.InputArtifactNames...)
+	}
+	if a.OutputArtifacts != nil {
+		ea.OutputArtifacts = a.DeepCopy().OutputArtifacts
+	}
+	if a.Timeout != nil {
+		ea.Timeout = a.Timeout.DeepCopy()
+	}
+	if a.Parallelism != 0 {
+		ea.Parallelism = a.Parallelism
+	}
+	if a.Options != nil {
+		ea.Options = a.DeepCopy().Options
+	}
+	return ea, nil
+}
+
+// uses returns the expanded action that ref refers to.
+func (e *expander) uses(ref string) (*crv1alpha1.BlueprintAction, error) {
+	parts := strings.Split(ref, usesSeparator)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("Invalid reference %s to an action, expected <blueprint>/<action>", ref)
+	}
+	bp, ok := e.blueprints[parts[0]]
+	if !ok {
+		if e.get == nil {
+			return nil, errors.Errorf("Cannot fetch blueprint %s used by %s", parts[0], e.stack[len(e.stack)-1])
+		}
+		var err error
+		if bp, err = e.get(parts[0]); err != nil {
+			return nil, errors.Wrapf(err, "Failed to fetch blueprint %s used by %s", parts[0], e.stack[len(e.stack)-1])
+		}
+		e.blueprints[parts[0]] = bp
+	}
+	return e.action(bp, parts[1])
+}
+
+// phases replaces the phases that use other actions by the phases of those
+// actions. The dependencies of the resulting phases are explicit.
+func (e *expander) phases(ref string, phases []crv1alpha1.BlueprintPhase) ([]crv1alpha1.BlueprintPhase, error) {
+	deps, err := phaseDependencies(phases)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Action %s has invalid phase dependencies", ref)
+	}
+	groups := make([][]crv1alpha1.BlueprintPhase, len(phases))
+	for i, p := range phases {
+		if p.Uses == "" {
+			p.DependsOn = nil
+			groups[i] = []crv1alpha1.BlueprintPhase{p}
+			continue
+		}
+		if p.Func != "" || p.Args != nil || p.ObjectRefs != nil || p.If != "" || p.RetryPolicy != nil || p.Timeout != nil {
+			return nil, errors.Errorf("Phase %s of action %s uses %s and may only set its name and dependencies", p.Name, ref, p.Uses)
+		}
+		ua, err := e.uses(p.Uses)
+		if err != nil {
+			return nil, err
+		}
+		if len(ua.Phases) == 0 {
+			return nil, errors.Errorf("Phase %s of action %s uses %s, which has no phases", p.Name, ref, p.Uses)
+		}
+		if groups[i], err = importPhases(p.Name, ua.Phases); err != nil {
+			return nil, errors.Wrapf(err, "Failed to import the phases of %s into action %s", p.Uses, ref)
+		}
+	}
+	expanded := make([]crv1alpha1.BlueprintPhase, 0, len(phases))
+	names := make(map[string]bool, len(phases))
+	for i, g := range groups {
+		var outer []string
+		for _, j := range deps[i] {
+			for _, p := range groups[j] {
+				outer = append(outer, p.Name)
+			}
+		}
+		for _, p := range g {
+			if names[p.Name] {
+				return nil, errors.Errorf("Phase name %s of action %s is not unique once the phases of the actions it uses are imported", p.Name, ref)
+			}
+			names[p.Name] = true
+			if len(p.DependsOn) == 0 {
+				p.DependsOn = append([]string(nil), outer...)
+			}
+			expanded = append(expanded, p)
+		}
+	}
+	return expanded, nil
+}
+
+// importPhases returns copies of the phases whose names are prefixed with
+// prefix. Their dependencies on each other are made explicit and their
+// templates refer to each other by their new names.
+func importPhases(prefix string, phases []crv1alpha1.BlueprintPhase) ([]crv1alpha1.BlueprintPhase, error) {
+	deps, err := phaseDependencies(phases)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(phases))
+	for _, p := range phases {
+		names[p.Name] = prefix + phaseNameSeparator + p.Name
+	}
+	imported := make([]crv1alpha1.BlueprintPhase, 0, len(phases))
+	for i, p := range phases {
+		ip := *p.DeepCopy()
+		ip.Name = names[p.Name]
+		ip.DependsOn = nil
+		for _, j := range deps[i] {
+			ip.DependsOn = append(ip.DependsOn, names[phases[j].Name])
+		}
+		args, err := param.RenamePhases(p.Args, names)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to rename the phases in the arguments of phase %s", p.Name)
+		}
+		ip.Args, _ = args.(map[string]interface{})
+		objs, err := param.RenamePhases(p.ObjectRefs, names)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to rename the phases in the object references of phase %s", p.Name)
+		}
+		ip.ObjectRefs, _ = objs.(map[string]crv1alpha1.ObjectReference)
+		cond, err := param.RenamePhases(p.If, names)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to rename the phases in the condition of phase %s", p.Name)
+		}
+		ip.If, _ = cond.(string)
+		imported = append(imported, ip)
+	}
+	return imported, nil
+}
+
+// checkExpanded returns an error if the action uses other actions, since
+// those must be expanded with ExpandBlueprint before its phases are created.
+func checkExpanded(action string, a crv1alpha1.BlueprintAction) error {
+	if actionUses(a) {
+		return errors.Errorf("Action {%s} uses other actions and must be expanded first", action)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package kanister
+
+import (
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type ComposeSuite struct{}
+
+var _ = Suite(&ComposeSuite{})
+
+func newComposeBlueprint(name string, actions map[string]*crv1alpha1.BlueprintAction) *crv1alpha1.Blueprint {
+	return &crv1alpha1.Blueprint{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Actions:    actions,
+	}
+}
+
+func (s *ComposeSuite) TestExpandBlueprint(c *C) {
+	common := newComposeBlueprint("common", map[string]*crv1alpha1.BlueprintAction{
+		"quiesce": {
+			Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "freeze", Func: "KubeExec"},
+				{Name: "flush", Func: "KubeExec", Args: map[string]interface{}{
+					"command": []interface{}{"flush", "{{ .Phases.freeze.Output.id }}"},
+				}},
+			},
+		},
+		"unquiesce": {
+			Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "thaw", Func: "KubeExec"},
+			},
+		},
+		"backup": {
+			Kind:               "StatefulSet",
+			InputArtifactNames: []string{"config"},
+			OutputArtifacts: map[string]crv1alpha1.Artifact{
+				"dump": {KeyValue: map[string]string{"path": "{{ .Phases.dump.Output.path }}"}},
+			},
+			Phases: []crv1alpha1.BlueprintPhase{
+				{Name: "dump", Func: "KubeTask"},
+			},
+			DeferPhase: &crv1alpha1.BlueprintPhase{Name: "cleanup", Func: "KubeTask"},
+		},
+		"cycle": {Uses: "other/cycle"},
+		"empty": {},
+	})
+	other := newComposeBlueprint("other", map[string]*crv1alpha1.BlueprintAction{
+		"cycle": {Uses: "common/cycle"},
+	})
+	get := func(name string) (*crv1alpha1.Blueprint, error) {
+		switch name {
+		case "common":
+			return common, nil
+		case "other":
+			return other, nil
+		}
+		return nil, errors.Errorf("Blueprint %s not found", name)
+	}
+
+	for _, tc := range []struct {
+		action  *crv1alpha1.BlueprintAction
+		check   func(c *C, a *crv1alpha1.BlueprintAction)
+		checker Checker
+	}{
+		{
+			// Actions that do not use other actions are copied.
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "a", Func: "KubeTask"},
+				},
+			},
+			check: func(c *C, a *crv1alpha1.BlueprintAction) {
+				c.Assert(a.Phases, DeepEquals, []crv1alpha1.BlueprintPhase{{Name: "a", Func: "KubeTask"}})
+			},
+			checker: IsNil,
+		},
+		{
+			// An action inherits the action it uses, except for the
+			// fields it sets itself.
+			action: &crv1alpha1.BlueprintAction{
+				Name:               "backup",
+				Uses:               "common/backup",
+				InputArtifactNames: []string{"secret"},
+				Parallelism:        2,
+			},
+			check: func(c *C, a *crv1alpha1.BlueprintAction) {
+				c.Assert(a.Uses, Equals, "")
+				c.Assert(a.Name, Equals, "backup")
+				c.Assert(a.Kind, Equals, "StatefulSet")
+				c.Assert(a.InputArtifactNames, DeepEquals, []string{"secret"})
+				c.Assert(a.Parallelism, Equals, 2)
+				c.Assert(a.OutputArtifacts, DeepEquals, common.Actions["backup"].OutputArtifacts)
+				c.Assert(a.Phases, DeepEquals, common.Actions["backup"].Phases)
+				c.Assert(a.DeferPhase, DeepEquals, common.Actions["backup"].DeferPhase)
+			},
+			checker: IsNil,
+		},
+		{
+			// Phases are imported in place of the phase that uses them.
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce", Uses: "common/quiesce"},
+					{Name: "snapshot", Func: "CreateVolumeSnapshot"},
+					{Name: "unquiesce", Uses: "common/unquiesce"},
+				},
+			},
+			check: func(c *C, a *crv1alpha1.BlueprintAction) {
+				c.Assert(a.Phases, DeepEquals, []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce_freeze", Func: "KubeExec", DependsOn: nil},
+					{Name: "quiesce_flush", Func: "KubeExec", DependsOn: []string{"quiesce_freeze"}, Args: map[string]interface{}{
+						"command": []interface{}{"flush", "{{.Phases.quiesce_freeze.Output.id}}"},
+					}},
+					{Name: "snapshot", Func: "CreateVolumeSnapshot", DependsOn: []string{"quiesce_freeze", "quiesce_flush"}},
+					{Name: "unquiesce_thaw", Func: "KubeExec", DependsOn: []string{"snapshot"}},
+				})
+			},
+			checker: IsNil,
+		},
+		{
+			// Dependencies on a phase that uses an action are
+			// dependencies on all of its phases.
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "snapshot", Func: "CreateVolumeSnapshot", DependsOn: []string{"quiesce"}},
+					{Name: "quiesce", Uses: "common/quiesce"},
+					{Name: "backup", Func: "KubeTask"},
+				},
+			},
+			check: func(c *C, a *crv1alpha1.BlueprintAction) {
+				c.Assert(a.Phases, HasLen, 4)
+				c.Assert(a.Phases[0].Name, Equals, "snapshot")
+				c.Assert(a.Phases[0].DependsOn, DeepEquals, []string{"quiesce_freeze", "quiesce_flush"})
+				c.Assert(a.Phases[1].Name, Equals, "quiesce_freeze")
+				c.Assert(a.Phases[1].DependsOn, HasLen, 0)
+				c.Assert(a.Phases[2].Name, Equals, "quiesce_flush")
+				c.Assert(a.Phases[2].DependsOn, DeepEquals, []string{"quiesce_freeze"})
+				c.Assert(a.Phases[3].Name, Equals, "backup")
+				c.Assert(a.Phases[3].DependsOn, HasLen, 0)
+				_, err := PrecedingPhases(*a)
+				c.Assert(err, IsNil)
+			},
+			checker: IsNil,
+		},
+		{
+			// Actions of the blueprint itself can be used.
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "first", Uses: "test/local"},
+				},
+			},
+			check: func(c *C, a *crv1alpha1.BlueprintAction) {
+				c.Assert(a.Phases, DeepEquals, []crv1alpha1.BlueprintPhase{
+					{Name: "first_local", Func: "KubeTask", DependsOn: nil},
+				})
+			},
+			checker: IsNil,
+		},
+		{
+			action:  &crv1alpha1.BlueprintAction{Uses: "common/cycle"},
+			checker: NotNil,
+		},
+		{
+			action:  &crv1alpha1.BlueprintAction{Uses: "test/action"},
+			checker: NotNil,
+		},
+		{
+			action:  &crv1alpha1.BlueprintAction{Uses: "missing/backup"},
+			checker: NotNil,
+		},
+		{
+			action:  &crv1alpha1.BlueprintAction{Uses: "common/missing"},
+			checker: NotNil,
+		},
+		{
+			action:  &crv1alpha1.BlueprintAction{Uses: "common"},
+			checker: NotNil,
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{
+				Uses:   "common/backup",
+				Phases: []crv1alpha1.BlueprintPhase{{Name: "a", Func: "KubeTask"}},
+			},
+			checker: NotNil,
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce", Uses: "common/quiesce", Func: "KubeTask"},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce", Uses: "common/empty"},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Imported phase names must not collide.
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "quiesce_freeze", Func: "KubeTask"},
+					{Name: "quiesce", Uses: "common/quiesce"},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{
+				Phases:     []crv1alpha1.BlueprintPhase{{Name: "a", Func: "KubeTask"}},
+				DeferPhase: &crv1alpha1.BlueprintPhase{Name: "cleanup", Uses: "common/unquiesce"},
+			},
+			checker: NotNil,
+		},
+	} {
+		bp := newComposeBlueprint("test", map[string]*crv1alpha1.BlueprintAction{
+			"action": tc.action,
+			"local": {
+				Phases: []crv1alpha1.BlueprintPhase{{Name: "local", Func: "KubeTask"}},
+			},
+		})
+		ebp, err := ExpandBlueprint(bp, get)
+		c.Check(err, tc.checker, Commentf("%#v", tc.action))
+		if err != nil {
+			continue
+		}
+		c.Assert(BlueprintUses(ebp), Equals, false)
+		tc.check(c, ebp.Actions["action"])
+	}
+}
+
+func (s *ComposeSuite) TestGetPhasesUnexpanded(c *C) {
+	bp := newComposeBlueprint("test", map[string]*crv1alpha1.BlueprintAction{
+		"action": {
+			Phases: []crv1alpha1.BlueprintPhase{{Name: "quiesce", Uses: "common/quiesce"}},
+		},
+	})
+	_, err := GetPhases(*bp, "action", param.TemplateParams{})
+	c.Assert(err, NotNil)
+	_, err = GetDeferPhase(*bp, "action", param.TemplateParams{})
+	c.Assert(err, NotNil)
+}
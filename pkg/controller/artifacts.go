@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// artifactSecretName returns the name of the Secret that stores the values of
+// the sensitive output artifacts of an action of the ActionSet.
+func artifactSecretName(as *crv1alpha1.ActionSet, aIDX int) string {
+	return fmt.Sprintf("%s-artifacts-%d", as.GetName(), aIDX)
+}
+
+// storeSensitiveArtifacts stores the values of the sensitive keys of the
+// rendered output artifacts of an action in a Secret owned by the ActionSet.
+// It returns the artifacts without those values, which refer to the Secret
+// instead.
+func (c *Controller) storeSensitiveArtifacts(as *crv1alpha1.ActionSet, aIDX int, arts map[string]crv1alpha1.Artifact) (map[string]crv1alpha1.Artifact, error) {
+	ref := actionSetSecretRef(as, artifactSecretName(as, aIDX))
+	sarts, data := param.SplitSensitiveArtifacts(arts, ref)
+	if len(data) == 0 {
+		return arts, nil
+	}
+	if err := c.storeActionSetSecret(as, ref, data); err != nil {
+		return nil, errors.Wrap(err, "Failed to store sensitive artifacts")
+	}
+	return sarts, nil
+}
+
+// actionSetSecretRef returns a reference to the named Secret in the namespace
+// of the ActionSet.
+func actionSetSecretRef(as *crv1alpha1.ActionSet, name string) crv1alpha1.ObjectReference {
+	return crv1alpha1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Name:       name,
+		Namespace:  as.GetNamespace(),
+	}
+}
+
+// storeActionSetSecret creates or updates the Secret ref with the data. The
+// Secret is owned by the ActionSet, so that it is deleted along with it. An
+// existing Secret is only updated if the ActionSet controls it.
+func (c *Controller) storeActionSetSecret(as *crv1alpha1.ActionSet, ref crv1alpha1.ObjectReference, data map[string][]byte) error {
+	isController := true
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: crv1alpha1.SchemeGroupVersion.String(),
+					Kind:       crv1alpha1.ActionSetResource.Kind,
+					Name:       as.GetName(),
+					UID:        as.GetUID(),
+					Controller: &isController,
+				},
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+	es, err := c.clientset.CoreV1().Secrets(ref.Namespace).Get(ref.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = c.clientset.CoreV1().Secrets(ref.Namespace).Create(s)
+	case err != nil:
+	case !controlledBy(es.ObjectMeta, as):
+		err = errors.Errorf("Secret is not controlled by ActionSet %s", as.GetName())
+	default:
+		// The action is run again after the controller restarted.
+		es.Data = data
+		_, err = c.clientset.CoreV1().Secrets(ref.Namespace).Update(es)
+	}
+	return errors.Wrapf(err, "Failed to store secret '%s:%s'", ref.Namespace, ref.Name)
+}
+
+// controlledBy returns whether the object is controlled by the ActionSet.
+func controlledBy(m metav1.ObjectMeta, as *crv1alpha1.ActionSet) bool {
+	ref := metav1.GetControllerOf(&m)
+	return ref != nil && ref.UID == as.GetUID()
+}
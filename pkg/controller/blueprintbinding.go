@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+func (c *Controller) onAddBlueprintBinding(bb *crv1alpha1.BlueprintBinding) error {
+	if err := validate.BlueprintBinding(bb); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Added invalid blueprint binding %s:", bb.GetName()), "InvalidBlueprintBinding", err, bb)
+		return nil
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Added blueprint binding %s", bb.GetName()), "Added", bb)
+	return nil
+}
+
+func (c *Controller) onUpdateBlueprintBinding(oldBB, newBB *crv1alpha1.BlueprintBinding) error {
+	if reflect.DeepEqual(oldBB.Spec, newBB.Spec) {
+		return nil
+	}
+	log.Infof("Updated BlueprintBinding '%s'", newBB.GetName())
+	if err := validate.BlueprintBinding(newBB); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Updated invalid blueprint binding %s:", newBB.GetName()), "InvalidBlueprintBinding", err, newBB)
+	}
+	return nil
+}
+
+func (c *Controller) onDeleteBlueprintBinding(bb *crv1alpha1.BlueprintBinding) error {
+	log.Infof("Deleted BlueprintBinding %s", bb.GetName())
+	return nil
+}
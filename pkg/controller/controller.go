@@ -24,7 +24,9 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	opkit "github.com/rook/operator-kit"
@@ -38,41 +40,104 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/util/workqueue"
 
 	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/binding"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned/scheme"
 	"github.com/kanisterio/kanister/pkg/eventer"
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/redact"
 	"github.com/kanisterio/kanister/pkg/validate"
 )
 
 // Controller represents a controller object for kanister custom resources
 type Controller struct {
 	config           *rest.Config
+	opts             Options
 	crClient         versioned.Interface
 	clientset        kubernetes.Interface
 	recorder         record.EventRecorder
 	actionSetTombMap sync.Map
+	// cancellingActionSets holds the keys of the ActionSets whose cancel is
+	// in progress.
+	cancellingActionSets sync.Map
+	// actionSetStatuses holds the last observed status of each watched
+	// ActionSet by key, which the metrics are counted from.
+	actionSetStatuses sync.Map
+	queue             workqueue.RateLimitingInterface
+	admission         *admission
+}
+
+// Options configure the controller. The zero value keeps finished
+// ActionSets unless they set a TTL.
+type Options struct {
+	// ActionSetTTL is how long finished ActionSets are kept if they do not
+	// set ttlSecondsAfterFinished. Zero keeps them.
+	ActionSetTTL time.Duration
+	// MaxActionSets is the number of finished ActionSets that are kept in a
+	// namespace. The oldest ones are deleted first. Zero keeps all of them.
+	MaxActionSets int
+	// ArchiveActionSets writes finished ActionSets as JSON to the location
+	// of their profile before they are deleted.
+	ArchiveActionSets bool
+	// RecoveryPolicy applies to the ActionSets that were left running by a
+	// controller that stopped. The default is RecoveryFail.
+	RecoveryPolicy RecoveryPolicy
+	// MaxConcurrentActions is the number of actions that run at the same
+	// time. Other ActionSets are queued. Zero means no limit.
+	MaxConcurrentActions int
+	// MaxConcurrentActionsPerNamespace is the number of actions of the
+	// ActionSets of a namespace that run at the same time. Zero means no
+	// limit.
+	MaxConcurrentActionsPerNamespace int
+	// CrossNamespaceAllowList lists the namespaces whose Blueprints and
+	// Profiles can be used by the ActionSets of other namespaces. ActionSets
+	// can always use those of their own namespace.
+	CrossNamespaceAllowList []string
 }
 
 // New create controller for watching kanister custom resources created
 func New(c *rest.Config) *Controller {
+	return NewWithOptions(c, Options{})
+}
+
+// NewWithOptions creates a controller with the given options.
+func NewWithOptions(c *rest.Config, opts Options) *Controller {
 	return &Controller{
-		config: c,
+		config:    c,
+		opts:      opts,
+		queue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "actionsets"),
+		admission: newAdmission(opts.MaxConcurrentActions, opts.MaxConcurrentActionsPerNamespace),
 	}
 }
 
-// StartWatch watches for instances of ActionSets and Blueprints acts on them.
+// StartWatch watches for instances of ActionSets, Blueprints,
+// BlueprintBindings, Schedules and RetentionPolicies and acts on them. It also
+// starts the runs of Schedules when they are due, periodically applies the
+// RetentionPolicies and deletes finished ActionSets.
 func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
+	return c.StartWatchNamespaces(ctx, []string{namespace})
+}
+
+// StartWatchNamespaces is like StartWatch for several namespaces. If they
+// include metav1.NamespaceAll, the custom resources of all namespaces are
+// watched. The controller must be able to access the custom resources of
+// each namespace.
+func (c *Controller) StartWatchNamespaces(ctx context.Context, namespaces []string) error {
+	namespaces = watchedNamespaces(namespaces)
 	crClient, err := versioned.NewForConfig(c.config)
 	if err != nil {
 		return errors.Wrap(err, "failed to get a CustomResource client")
 	}
-	if err := checkCRAccess(crClient, namespace); err != nil {
-		return err
+	for _, ns := range namespaces {
+		if err := checkCRAccess(crClient, ns); err != nil {
+			return errors.Wrapf(err, "Cannot watch %s", namespaceName(ns))
+		}
 	}
 	clientset, err := kubernetes.NewForConfig(c.config)
 	if err != nil {
@@ -81,17 +146,40 @@ func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 	c.crClient = crClient
 	c.clientset = clientset
 	c.recorder = eventer.NewEventRecorder(c.clientset, "Kanister Controller")
+	for _, ns := range namespaces {
+		if err := c.recoverInterruptedActionSets(ns); err != nil {
+			return err
+		}
+	}
+
+	for _, ns := range namespaces {
+		log.Infof("Watching %s", namespaceName(ns))
+		c.watchNamespace(ctx, ns)
+		go c.runSchedules(ctx, ns)
+		go c.runRetentionPolicies(ctx, ns)
+		go c.runActionSetGC(ctx, ns)
+	}
+	metrics.RegisterStateCounter(c.stateCounter())
+	go c.runActionSetWorkers(ctx)
+	return nil
+}
 
+// watchNamespace watches the custom resources of the namespace until the
+// context is cancelled.
+func (c *Controller) watchNamespace(ctx context.Context, namespace string) {
 	for cr, o := range map[opkit.CustomResource]runtime.Object{
-		crv1alpha1.ActionSetResource: &crv1alpha1.ActionSet{},
-		crv1alpha1.BlueprintResource: &crv1alpha1.Blueprint{},
+		crv1alpha1.ActionSetResource:        &crv1alpha1.ActionSet{},
+		crv1alpha1.BlueprintResource:        &crv1alpha1.Blueprint{},
+		crv1alpha1.BlueprintBindingResource: &crv1alpha1.BlueprintBinding{},
+		crv1alpha1.ScheduleResource:         &crv1alpha1.Schedule{},
+		crv1alpha1.RetentionPolicyResource:  &crv1alpha1.RetentionPolicy{},
 	} {
 		resourceHandlers := cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.onAdd,
 			UpdateFunc: c.onUpdate,
 			DeleteFunc: c.onDelete,
 		}
-		watcher := opkit.NewWatcher(cr, namespace, resourceHandlers, crClient.CrV1alpha1().RESTClient())
+		watcher := opkit.NewWatcher(cr, namespace, resourceHandlers, c.crClient.CrV1alpha1().RESTClient())
 		// TODO: remove this tmp channel once https://github.com/rook/operator-kit/pull/11 is merged.
 		chTmp := make(chan struct{})
 		go func() {
@@ -102,7 +190,6 @@ func (c *Controller) StartWatch(ctx context.Context, namespace string) error {
 		}()
 		go watcher.Watch(o, chTmp)
 	}
-	return nil
 }
 
 func checkCRAccess(cli versioned.Interface, ns string) error {
@@ -112,9 +199,18 @@ func checkCRAccess(cli versioned.Interface, ns string) error {
 	if _, err := cli.CrV1alpha1().Blueprints(ns).List(v1.ListOptions{}); err != nil {
 		return errors.Wrap(err, "Could not list Blueprints")
 	}
+	if _, err := cli.CrV1alpha1().BlueprintBindings(ns).List(v1.ListOptions{}); err != nil {
+		return errors.Wrap(err, "Could not list BlueprintBindings")
+	}
 	if _, err := cli.CrV1alpha1().Profiles(ns).List(v1.ListOptions{}); err != nil {
 		return errors.Wrap(err, "Could not list Profiles")
 	}
+	if _, err := cli.CrV1alpha1().Schedules(ns).List(v1.ListOptions{}); err != nil {
+		return errors.Wrap(err, "Could not list Schedules")
+	}
+	if _, err := cli.CrV1alpha1().RetentionPolicies(ns).List(v1.ListOptions{}); err != nil {
+		return errors.Wrap(err, "Could not list RetentionPolicies")
+	}
 	return nil
 }
 
@@ -127,13 +223,24 @@ func (c *Controller) onAdd(obj interface{}) {
 	o = o.DeepCopyObject()
 	switch v := o.(type) {
 	case *crv1alpha1.ActionSet:
-		if err := c.onAddActionSet(v); err != nil {
-			log.Errorf("Callback onAddActionSet() failed: %+v", err)
-		}
+		c.observeActionSet(v)
+		c.enqueueActionSet(v)
 	case *crv1alpha1.Blueprint:
 		if err := c.onAddBlueprint(v); err != nil {
 			log.Errorf("Callback onAddBlueprint() failed: %+v", err)
 		}
+	case *crv1alpha1.BlueprintBinding:
+		if err := c.onAddBlueprintBinding(v); err != nil {
+			log.Errorf("Callback onAddBlueprintBinding() failed: %+v", err)
+		}
+	case *crv1alpha1.Schedule:
+		if err := c.onAddSchedule(v); err != nil {
+			log.Errorf("Callback onAddSchedule() failed: %+v", err)
+		}
+	case *crv1alpha1.RetentionPolicy:
+		if err := c.onAddRetentionPolicy(v); err != nil {
+			log.Errorf("Callback onAddRetentionPolicy() failed: %+v", err)
+		}
 	default:
 		log.Errorf("Unknown object type <%T>", o)
 	}
@@ -143,9 +250,10 @@ func (c *Controller) onUpdate(oldObj, newObj interface{}) {
 	switch old := oldObj.(type) {
 	case *crv1alpha1.ActionSet:
 		new := newObj.(*crv1alpha1.ActionSet)
+		c.observeActionSet(new)
 		if err := c.onUpdateActionSet(old, new); err != nil {
-			bpName := new.Spec.Actions[0].Blueprint
-			bp, _ := c.crClient.CrV1alpha1().Blueprints(new.GetNamespace()).Get(bpName, v1.GetOptions{})
+			bpName := actionBlueprint(new, 0)
+			bp := c.eventBlueprint(new.GetNamespace(), bpName)
 			c.logAndErrorEvent("Callback onUpdateActionSet() failed:", "Error", err, new, bp)
 
 		}
@@ -154,6 +262,21 @@ func (c *Controller) onUpdate(oldObj, newObj interface{}) {
 		if err := c.onUpdateBlueprint(old, new); err != nil {
 			c.logAndErrorEvent("Callback onUpdateBlueprint() failed:", "Error", err, new)
 		}
+	case *crv1alpha1.BlueprintBinding:
+		new := newObj.(*crv1alpha1.BlueprintBinding)
+		if err := c.onUpdateBlueprintBinding(old, new); err != nil {
+			c.logAndErrorEvent("Callback onUpdateBlueprintBinding() failed:", "Error", err, new)
+		}
+	case *crv1alpha1.Schedule:
+		new := newObj.(*crv1alpha1.Schedule)
+		if err := c.onUpdateSchedule(old, new); err != nil {
+			c.logAndErrorEvent("Callback onUpdateSchedule() failed:", "Error", err, new)
+		}
+	case *crv1alpha1.RetentionPolicy:
+		new := newObj.(*crv1alpha1.RetentionPolicy)
+		if err := c.onUpdateRetentionPolicy(old, new); err != nil {
+			c.logAndErrorEvent("Callback onUpdateRetentionPolicy() failed:", "Error", err, new)
+		}
 	default:
 		log.Errorf("Unknown object type <%T>", oldObj)
 	}
@@ -162,40 +285,38 @@ func (c *Controller) onUpdate(oldObj, newObj interface{}) {
 func (c *Controller) onDelete(obj interface{}) {
 	switch v := obj.(type) {
 	case *crv1alpha1.ActionSet:
+		c.forgetActionSet(v)
 		if err := c.onDeleteActionSet(v); err != nil {
-			bpName := v.Spec.Actions[0].Blueprint
-			bp, _ := c.crClient.CrV1alpha1().Blueprints(v.GetNamespace()).Get(bpName, v1.GetOptions{})
+			bpName := actionBlueprint(v, 0)
+			bp := c.eventBlueprint(v.GetNamespace(), bpName)
 			c.logAndErrorEvent("Callback onDeleteActionSet() failed:", "Error", err, v, bp)
 		}
 	case *crv1alpha1.Blueprint:
 		if err := c.onDeleteBlueprint(v); err != nil {
 			c.logAndErrorEvent("Callback onDeleteBlueprint() failed:", "Error", err, v)
 		}
+	case *crv1alpha1.BlueprintBinding:
+		if err := c.onDeleteBlueprintBinding(v); err != nil {
+			log.Errorf("Callback onDeleteBlueprintBinding() failed: %+v", err)
+		}
+	case *crv1alpha1.Schedule:
+		if err := c.onDeleteSchedule(v); err != nil {
+			log.Errorf("Callback onDeleteSchedule() failed: %+v", err)
+		}
+	case *crv1alpha1.RetentionPolicy:
+		if err := c.onDeleteRetentionPolicy(v); err != nil {
+			log.Errorf("Callback onDeleteRetentionPolicy() failed: %+v", err)
+		}
 	default:
 		log.Errorf("Unknown object type <%T>", obj)
 	}
 }
 
-func (c *Controller) onAddActionSet(as *crv1alpha1.ActionSet) error {
-	as, err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Get(as.GetName(), v1.GetOptions{})
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	if err := validate.ActionSet(as); err != nil {
-		return err
-	}
-	c.initActionSetStatus(as)
-	as, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Get(as.GetName(), v1.GetOptions{})
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	if err := validate.ActionSet(as); err != nil {
-		return err
-	}
-	return c.handleActionSet(as)
-}
-
 func (c *Controller) onAddBlueprint(bp *crv1alpha1.Blueprint) error {
+	if err := c.validateBlueprint(bp); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Added invalid blueprint %s:", bp.GetName()), "InvalidBlueprint", err, bp)
+		return nil
+	}
 	c.logAndSuccessEvent(fmt.Sprintf("Added blueprint %s", bp.GetName()), "Added", bp)
 	return nil
 }
@@ -205,6 +326,9 @@ func (c *Controller) onUpdateActionSet(oldAS, newAS *crv1alpha1.ActionSet) error
 		log.Infof("Updated ActionSet '%s'", newAS.Name)
 		return err
 	}
+	if newAS.Spec.Cancel && newAS.Status != nil && (newAS.Status.State == crv1alpha1.StatePending || newAS.Status.State == crv1alpha1.StateQueued || newAS.Status.State == crv1alpha1.StateRunning) {
+		return c.cancelActionSet(newAS)
+	}
 	if newAS.Status == nil || newAS.Status.State != crv1alpha1.StateRunning {
 		if newAS.Status == nil {
 			log.Infof("Updated ActionSet '%s' Status->nil", newAS.Name)
@@ -227,29 +351,116 @@ func (c *Controller) onUpdateActionSet(oldAS, newAS *crv1alpha1.ActionSet) error
 		return nil
 	}
 	return reconcile.ActionSet(context.TODO(), c.crClient.CrV1alpha1(), newAS.GetNamespace(), newAS.GetName(), func(ras *crv1alpha1.ActionSet) error {
-		ras.Status.State = crv1alpha1.StateComplete
+		setActionSetState(ras.Status, crv1alpha1.StateComplete, "Complete", "")
 		return nil
 	})
 }
 
 func (c *Controller) onUpdateBlueprint(oldBP, newBP *crv1alpha1.Blueprint) error {
 	log.Infof("Updated Blueprint '%s' from %#v to %#v", newBP.Name, oldBP, newBP)
+	if err := c.validateBlueprint(newBP); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Updated invalid blueprint %s:", newBP.GetName()), "InvalidBlueprint", err, newBP)
+	}
 	return nil
 }
 
+// validateBlueprint expands the actions that the blueprint uses and checks
+// the structure of the expanded blueprint and then the templates of its
+// phases against the template parameters.
+func (c *Controller) validateBlueprint(bp *crv1alpha1.Blueprint) error {
+	ebp, err := kanister.ExpandBlueprint(bp, c.blueprintGetter(bp.GetNamespace()))
+	if err != nil {
+		return err
+	}
+	if err := validate.Blueprint(ebp); err != nil {
+		return err
+	}
+	return validate.BlueprintTemplates(ebp)
+}
+
+// errActionSetDeleted is the reason the actions of a deleted ActionSet are
+// stopped with.
+var errActionSetDeleted = errors.New("ActionSet was deleted")
+
 func (c *Controller) onDeleteActionSet(as *crv1alpha1.ActionSet) error {
 	asName := as.GetName()
 	log.Infof("Deleted ActionSet %s", asName)
-	v, ok := c.actionSetTombMap.Load(asName)
-	if !ok {
+	c.removeWaitingActionSet(as.GetNamespace() + "/" + asName)
+	for _, t := range c.removeActionTombs(as) {
+		t.Kill(errActionSetDeleted)
+	}
+	return nil
+}
+
+// actionTombKey returns the key of the tomb of an ActionSet's action in the
+// actionSetTombMap. ActionSets of different namespaces may have the same
+// name.
+func actionTombKey(as *crv1alpha1.ActionSet, aIDX int) string {
+	return fmt.Sprintf("%s/%s/%d", as.GetNamespace(), as.GetName(), aIDX)
+}
+
+// removeActionTombs removes the tombs of the ActionSet's actions from the
+// actionSetTombMap and returns them.
+func (c *Controller) removeActionTombs(as *crv1alpha1.ActionSet) []*tomb.Tomb {
+	if as.Spec == nil {
 		return nil
 	}
-	t, castOk := v.(*tomb.Tomb)
-	if !castOk {
+	var ts []*tomb.Tomb
+	for i := range as.Spec.Actions {
+		key := actionTombKey(as, i)
+		v, ok := c.actionSetTombMap.Load(key)
+		if !ok {
+			continue
+		}
+		c.actionSetTombMap.Delete(key)
+		if t, castOk := v.(*tomb.Tomb); castOk {
+			ts = append(ts, t)
+		}
+	}
+	return ts
+}
+
+// cancelActionSet stops the execution of the ActionSet's actions. Once they
+// have stopped, the ActionSet is moved to the cancelled state. The status of
+// its phases is kept. The ActionSet is updated while its actions stop, so
+// calls made before the first cancel has finished are ignored.
+func (c *Controller) cancelActionSet(as *crv1alpha1.ActionSet) error {
+	key := as.GetNamespace() + "/" + as.GetName()
+	if _, ok := c.cancellingActionSets.LoadOrStore(key, struct{}{}); ok {
 		return nil
 	}
-	t.Kill(nil) // TODO: @Deepika Give reason for ActionSet kill
-	c.actionSetTombMap.Delete(asName)
+	c.removeWaitingActionSet(key)
+	ts := c.removeActionTombs(as)
+	for _, t := range ts {
+		t.Kill(nil)
+	}
+	go func() {
+		defer c.cancellingActionSets.Delete(key)
+		// Phases stop when their context is cancelled. This also stops the
+		// pods they started.
+		for _, t := range ts {
+			_ = t.Wait()
+		}
+		var cancelled bool
+		if err := reconcile.ActionSet(context.TODO(), c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+			cancelled = ras.Status.State == crv1alpha1.StatePending || ras.Status.State == crv1alpha1.StateQueued || ras.Status.State == crv1alpha1.StateRunning
+			if !cancelled {
+				return nil
+			}
+			now := v1.Now()
+			for i := range ras.Status.Actions {
+				endRunningPhases(&ras.Status.Actions[i], crv1alpha1.StateCancelled, "", now)
+			}
+			setActionSetState(ras.Status, crv1alpha1.StateCancelled, "Cancelled", "ActionSet was cancelled")
+			return nil
+		}); err != nil {
+			c.logAndErrorEvent(fmt.Sprintf("Failed to cancel ActionSet %s:", as.GetName()), "Error", err, as)
+			return
+		}
+		if cancelled {
+			c.logAndSuccessEvent(fmt.Sprintf("Cancelled ActionSet %s", as.GetName()), "ActionSetCancelled", as)
+		}
+	}()
 	return nil
 }
 
@@ -274,15 +485,24 @@ func (c *Controller) initActionSetStatus(as *crv1alpha1.ActionSet) {
 		var actionStatus *crv1alpha1.ActionStatus
 		actionStatus, err = c.initialActionStatus(as.GetNamespace(), a)
 		if err != nil {
-			bp, _ := c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(a.Blueprint, v1.GetOptions{})
+			bp := c.eventBlueprint(as.GetNamespace(), a.Blueprint)
 			reason := fmt.Sprintf("ActionSetFailed Action: %s", a.Name)
 			c.logAndErrorEvent("Could not get initial action:", reason, err, as, bp)
 			break
 		}
 		actions = append(actions, *actionStatus)
 	}
+	if err == nil {
+		if from, ok := as.GetAnnotations()[crv1alpha1.ResumeFromAnnotation]; ok {
+			if err = c.resumeActionStatus(as.GetNamespace(), from, actions); err != nil {
+				c.logAndErrorEvent(fmt.Sprintf("Could not resume ActionSet %s:", from), "ActionSetFailed", err, as)
+			} else {
+				c.logAndSuccessEvent(fmt.Sprintf("Resuming ActionSet %s", from), "Resumed", as)
+			}
+		}
+	}
 	if err != nil {
-		as.Status.State = crv1alpha1.StateFailed
+		setActionSetState(as.Status, crv1alpha1.StateFailed, "InitFailed", err.Error())
 	} else {
 		as.Status.State = crv1alpha1.StatePending
 		as.Status.Actions = actions
@@ -292,44 +512,163 @@ func (c *Controller) initActionSetStatus(as *crv1alpha1.ActionSet) {
 	}
 }
 
+// resumeActionStatus copies the state and output of the phases that
+// completed or were skipped in the named ActionSet into actions.
+func (c *Controller) resumeActionStatus(namespace, name string, actions []crv1alpha1.ActionStatus) error {
+	ras, err := c.crClient.CrV1alpha1().ActionSets(namespace).Get(name, v1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to query resumed ActionSet")
+	}
+	if ras.Status == nil || (ras.Status.State != crv1alpha1.StateFailed && ras.Status.State != crv1alpha1.StateCancelled) {
+		return errors.Errorf("ActionSet %s has not failed or been cancelled", name)
+	}
+	if ras.Spec != nil && ras.Spec.DryRun {
+		return errors.Errorf("ActionSet %s is a dry run", name)
+	}
+	if len(ras.Status.Actions) != len(actions) {
+		return errors.Errorf("ActionSet %s has %d actions, expected %d", name, len(ras.Status.Actions), len(actions))
+	}
+	for i, ra := range ras.Status.Actions {
+		a := &actions[i]
+		if ra.Name != a.Name || ra.Blueprint != a.Blueprint || len(ra.Phases) != len(a.Phases) {
+			return errors.Errorf("Action %s of ActionSet %s does not match action %s", ra.Name, name, a.Name)
+		}
+		for j, rp := range ra.Phases {
+			if rp.Name != a.Phases[j].Name {
+				return errors.Errorf("Phase %s of ActionSet %s does not match phase %s", rp.Name, name, a.Phases[j].Name)
+			}
+			if rp.State == crv1alpha1.StateComplete || rp.State == crv1alpha1.StateSkipped {
+				a.Phases[j].State = rp.State
+				a.Phases[j].Output = rp.Output
+				a.Phases[j].OutputRef = rp.OutputRef
+			}
+		}
+	}
+	return nil
+}
+
+// getBlueprint fetches the Blueprint that an ActionSet in the namespace
+// refers to and expands the actions of other Blueprints in the namespace of
+// the Blueprint that it uses.
+func (c *Controller) getBlueprint(namespace, ref string) (*crv1alpha1.Blueprint, error) {
+	ns, name, err := c.blueprintRef(namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := c.crClient.CrV1alpha1().Blueprints(ns).Get(name, v1.GetOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return kanister.ExpandBlueprint(bp, c.blueprintGetter(ns))
+}
+
+// eventBlueprint returns the Blueprint that an ActionSet in the namespace
+// refers to, so that events are also recorded on it. If it cannot be fetched,
+// an empty Blueprint is returned, on which no event is recorded.
+func (c *Controller) eventBlueprint(namespace, ref string) *crv1alpha1.Blueprint {
+	ns, name, err := c.blueprintRef(namespace, ref)
+	if err != nil {
+		return &crv1alpha1.Blueprint{}
+	}
+	bp, err := c.crClient.CrV1alpha1().Blueprints(ns).Get(name, v1.GetOptions{})
+	if err != nil {
+		return &crv1alpha1.Blueprint{}
+	}
+	return bp
+}
+
+// blueprintGetter returns a getter of the Blueprints in the namespace.
+func (c *Controller) blueprintGetter(namespace string) kanister.BlueprintGetter {
+	return func(name string) (*crv1alpha1.Blueprint, error) {
+		bp, err := c.crClient.CrV1alpha1().Blueprints(namespace).Get(name, v1.GetOptions{})
+		return bp, errors.WithStack(err)
+	}
+}
+
 func (c *Controller) initialActionStatus(namespace string, a crv1alpha1.ActionSpec) (*crv1alpha1.ActionStatus, error) {
-	if a.Blueprint == "" {
-		// TODO: If no blueprint is specified, we should consider a default.
-		return nil, errors.New("Blueprint not specified")
+	bpName := a.Blueprint
+	if bpName == "" {
+		var err error
+		if bpName, err = binding.Resolve(context.TODO(), c.clientset, c.crClient, namespace, a.Object); err != nil {
+			return nil, errors.Wrap(err, "Blueprint not specified")
+		}
+	}
+	if a.Profile != nil {
+		if err := c.checkCrossNamespaceRef(namespace, a.Profile.Namespace); err != nil {
+			return nil, errors.Wrapf(err, "Cannot use profile %s/%s", a.Profile.Namespace, a.Profile.Name)
+		}
+	}
+	if err := c.checkArtifactRefs(namespace, a.Artifacts); err != nil {
+		return nil, err
 	}
-	bp, err := c.crClient.CrV1alpha1().Blueprints(namespace).Get(a.Blueprint, v1.GetOptions{})
+	bp, err := c.getBlueprint(namespace, bpName)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to query blueprint")
 	}
 	bpa, ok := bp.Actions[a.Name]
 	if !ok {
-		return nil, errors.Errorf("Action %s for object kind %s not found in blueprint %s", a.Name, a.Object.Kind, a.Blueprint)
+		return nil, errors.Errorf("Action %s for object kind %s not found in blueprint %s", a.Name, a.Object.Kind, bpName)
+	}
+	if _, err := param.ActionOptions(bpa.Options, a.Options); err != nil {
+		return nil, errors.Wrapf(err, "Invalid options for action %s of blueprint %s", a.Name, bpName)
 	}
 	phases := make([]crv1alpha1.Phase, 0, len(bpa.Phases))
 	for _, p := range bpa.Phases {
 		phases = append(phases, crv1alpha1.Phase{
-			Name:  p.Name,
-			State: crv1alpha1.StatePending,
+			Name:      p.Name,
+			State:     crv1alpha1.StatePending,
+			DependsOn: p.DependsOn,
 		})
 	}
+	var deferPhase *crv1alpha1.Phase
+	if bpa.DeferPhase != nil {
+		deferPhase = &crv1alpha1.Phase{
+			Name:  bpa.DeferPhase.Name,
+			State: crv1alpha1.StatePending,
+		}
+	}
 	return &crv1alpha1.ActionStatus{
-		Name:      a.Name,
-		Object:    a.Object,
-		Blueprint: a.Blueprint,
-		Phases:    phases,
-		Artifacts: bpa.OutputArtifacts,
+		Name:       a.Name,
+		Object:     a.Object,
+		Blueprint:  bpName,
+		Phases:     phases,
+		DeferPhase: deferPhase,
+		Artifacts:  bpa.OutputArtifacts,
 	}, nil
 
 }
 
+// actionBlueprint returns the name of the Blueprint of the ActionSet's
+// action. If the action does not name one, it is resolved from the
+// BlueprintBindings when the ActionSet is initialized.
+func actionBlueprint(as *crv1alpha1.ActionSet, aIDX int) string {
+	if as.Status != nil && aIDX < len(as.Status.Actions) && as.Status.Actions[aIDX].Blueprint != "" {
+		return as.Status.Actions[aIDX].Blueprint
+	}
+	return as.Spec.Actions[aIDX].Blueprint
+}
+
+// handleActionSet starts the actions of a pending or queued ActionSet. The
+// slots of its actions were reserved by the admission and are released when
+// the actions stop, or here if they are not started.
 func (c *Controller) handleActionSet(as *crv1alpha1.ActionSet) (err error) {
+	ns, reserved, started := as.GetNamespace(), len(as.Spec.Actions), 0
+	defer func() {
+		c.releaseActions(ns, reserved-started)
+	}()
 	if as.Status == nil {
 		return errors.New("ActionSet was not initialized")
 	}
-	if as.Status.State != crv1alpha1.StatePending {
+	if as.Status.State != crv1alpha1.StatePending && as.Status.State != crv1alpha1.StateQueued {
 		return nil
 	}
-	as.Status.State = crv1alpha1.StateRunning
+	if as.Spec.Cancel {
+		return c.cancelActionSet(as)
+	}
+	setActionSetState(as.Status, crv1alpha1.StateRunning, "Started", "")
+	for i := range as.Status.Actions {
+		as.Status.Actions[i].StartTime = as.Status.StartTime
+	}
 	if as, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as); err != nil {
 		return errors.WithStack(err)
 	}
@@ -338,108 +677,174 @@ func (c *Controller) handleActionSet(as *crv1alpha1.ActionSet) (err error) {
 		if err = c.runAction(ctx, as, i); err != nil {
 			// If runAction returns an error, it is a failure in the synchronous
 			// part of running the action.
-			bpName := as.Spec.Actions[i].Blueprint
-			bp, _ := c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(bpName, v1.GetOptions{})
+			bpName := actionBlueprint(as, i)
+			bp := c.eventBlueprint(as.GetNamespace(), bpName)
 			reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Status.Actions[i].Name)
 			c.logAndErrorEvent(fmt.Sprintf("Failed to launch Action %s:", as.GetName()), reason, err, as, bp)
-			as.Status.State = crv1alpha1.StateFailed
-			as.Status.Actions[i].Phases[0].State = crv1alpha1.StateFailed
+			setActionSetState(as.Status, crv1alpha1.StateFailed, "LaunchFailed", err.Error())
+			setActionError(&as.Status.Actions[i], err.Error())
+			as.Status.Actions[i].EndTime = as.Status.EndTime
+			if len(as.Status.Actions[i].Phases) > 0 {
+				as.Status.Actions[i].Phases[0].State = crv1alpha1.StateFailed
+				as.Status.Actions[i].Phases[0].Error = err.Error()
+			}
 			_, err = c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Update(as)
 			return errors.WithStack(err)
 		}
+		started++
 	}
 	log.Infof("Created actionset %s and started executing actions", as.GetName())
 	return nil
 }
 
+// cancelledDeferPhaseTimeout bounds the execution of the deferred phase of
+// a cancelled ActionSet.
+const cancelledDeferPhaseTimeout = 10 * time.Minute
+
 func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) error {
 	action := as.Spec.Actions[aIDX]
 	c.logAndSuccessEvent(fmt.Sprintf("Executing action %s", action.Name), "Started Action", as)
-	bpName := as.Spec.Actions[aIDX].Blueprint
-	bp, err := c.crClient.CrV1alpha1().Blueprints(as.GetNamespace()).Get(bpName, v1.GetOptions{})
+	bpName := actionBlueprint(as, aIDX)
+	bp, tp, err := c.actionParams(ctx, as, aIDX)
 	if err != nil {
-		return errors.WithStack(err)
+		return err
 	}
-	tp, err := param.New(ctx, c.clientset, c.crClient, action)
+	phases, err := kanister.GetPhases(*bp, action.Name, *tp)
 	if err != nil {
 		return err
 	}
-	phases, err := kanister.GetPhases(*bp, action.Name, *tp)
+	deferPhase, err := kanister.GetDeferPhase(*bp, action.Name, *tp)
+	if err != nil {
+		return err
+	}
+	// Phases that are already done were resumed from another ActionSet.
+	// Their output is made available to the remaining phases.
+	done := make([]bool, len(phases))
+	for i, p := range phases {
+		ps := as.Status.Actions[aIDX].Phases[i]
+		switch ps.State {
+		case crv1alpha1.StateComplete:
+			if err = param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects()); err != nil {
+				return err
+			}
+			output, err := c.phaseOutput(ps)
+			if err != nil {
+				return err
+			}
+			param.UpdatePhaseParams(ctx, tp, p.Name(), output)
+			done[i] = true
+		case crv1alpha1.StateSkipped:
+			done[i] = true
+		}
+	}
+	// The outputs that the sensitive output artifacts are rendered from are
+	// not recorded in the status either.
+	sensitive, err := param.SensitiveOutputs(as.Status.Actions[aIDX].Artifacts)
 	if err != nil {
 		return err
 	}
 	ns, name := as.GetNamespace(), as.GetName()
+	timeout := actionTimeout(action, bp.Actions[action.Name])
+	parallelism := bp.Actions[action.Name].Parallelism
 	var t *tomb.Tomb
 	t, ctx = tomb.WithContext(ctx)
-	c.actionSetTombMap.Store(as.Name, t)
+	c.actionSetTombMap.Store(actionTombKey(as, aIDX), t)
 	t.Go(func() error {
-		for i, p := range phases {
-			c.logAndSuccessEvent(fmt.Sprintf("Executing phase %s", p.Name()), "Started Phase", as)
-			err = param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects())
-			var output map[string]interface{}
-			var msg string
-			if err == nil {
-				output, err = p.Exec(ctx, *bp, action.Name, *tp)
-			} else {
-				msg = fmt.Sprintf("Failed to init phase params: %#v:", as.Status.Actions[aIDX].Phases[i])
+		defer c.releaseActions(ns, 1)
+		// The secrets of the action are redacted from logs and events while
+		// it runs.
+		defer redact.Register(param.SecretValues(*tp)...)()
+		defer metrics.ActionStarted()()
+		start := time.Now()
+		recordSuccess := func() {
+			metrics.ActionSucceeded(bpName, action.Name, action.Object.Namespace, action.Object.Kind, action.Object.Name, start, time.Now())
+		}
+		// The action and phase timeouts only bound the execution of phases.
+		// Status updates use the tomb's context so that a timeout can still
+		// be recorded.
+		actx, cancel := withTimeout(ctx, timeout)
+		defer cancel()
+		// Phases run as soon as the phases they depend on have completed.
+		succeeded := kanister.ExecPhases(phases, parallelism, func(i int) bool {
+			if done[i] {
+				return true
 			}
-			var rf func(*crv1alpha1.ActionSet) error
-			if err != nil {
-				rf = func(ras *crv1alpha1.ActionSet) error {
-					ras.Status.State = crv1alpha1.StateFailed
-					ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateFailed
-					return nil
-				}
-			} else {
-				rf = func(ras *crv1alpha1.ActionSet) error {
-					ras.Status.Actions[aIDX].Phases[i].State = crv1alpha1.StateComplete
-					ras.Status.Actions[aIDX].Phases[i].Output = output
-					return nil
-				}
+			ps := func(ras *crv1alpha1.ActionSet) *crv1alpha1.Phase {
+				return &ras.Status.Actions[aIDX].Phases[i]
 			}
-			if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, rf); rErr != nil {
-				reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
-				msg := fmt.Sprintf("Failed to update phase: %#v:", as.Status.Actions[aIDX].Phases[i])
-				c.logAndErrorEvent(msg, reason, rErr, as, bp)
-				return nil
+			return c.executePhase(ctx, actx, as, aIDX, bp, tp, phases[i], ps, phaseOutputSecretName(as, aIDX, strconv.Itoa(i)), sensitive)
+		})
+		// The deferred phase runs whether or not the other phases
+		// succeeded. It is not bound by the action's timeout so that it can
+		// clean up after a timed out action. If the ActionSet was cancelled
+		// it still runs, with a context of its own. It does not run once the
+		// ActionSet is deleted, since neither its status nor the Secrets it
+		// owns can be updated anymore.
+		if deferPhase != nil && t.Err() != errActionSetDeleted {
+			dctx := ctx
+			if ctx.Err() != nil {
+				var dcancel context.CancelFunc
+				dctx, dcancel = context.WithTimeout(context.Background(), cancelledDeferPhaseTimeout)
+				defer dcancel()
 			}
-			if err != nil {
-				reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
-				if msg == "" {
-					msg = fmt.Sprintf("Failed to execute phase: %#v:", as.Status.Actions[aIDX].Phases[i])
-				}
-				c.logAndErrorEvent(msg, reason, err, as, bp)
-				return nil
+			ps := func(ras *crv1alpha1.ActionSet) *crv1alpha1.Phase {
+				return ras.Status.Actions[aIDX].DeferPhase
 			}
-			param.UpdatePhaseParams(ctx, tp, p.Name(), output)
-			c.logAndSuccessEvent(fmt.Sprintf("Completed phase %s", p.Name()), "Ended Phase", as)
+			if !c.executePhase(dctx, dctx, as, aIDX, bp, tp, deferPhase, ps, phaseOutputSecretName(as, aIDX, deferPhaseIndex), sensitive) {
+				succeeded = false
+			}
+		}
+		if ctx.Err() != nil {
+			// The final state of a cancelled ActionSet is recorded by
+			// cancelActionSet.
+			return nil
+		}
+		if !succeeded {
+			c.setActionEndTime(ctx, as, aIDX)
+			return nil
 		}
 		// Check if output artifacts are present
 		artTpls := as.Status.Actions[aIDX].Artifacts
 		if len(artTpls) == 0 {
 			// No artifacts, set ActionSetStatus to complete
 			if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, func(ras *crv1alpha1.ActionSet) error {
-				ras.Status.State = crv1alpha1.StateComplete
+				now := v1.Now()
+				ras.Status.Actions[aIDX].EndTime = &now
+				setActionSetState(ras.Status, crv1alpha1.StateComplete, "Complete", "")
 				return nil
 			}); rErr != nil {
 				reason := fmt.Sprintf("ActionSetFailed Action: %s", action.Name)
 				msg := fmt.Sprintf("Failed to update ActionSet: %s", name)
 				c.logAndErrorEvent(msg, reason, err, as, bp)
+				return nil
 			}
+			recordSuccess()
 			return nil
 		}
-		// Render the artifacts
+		// Render the artifacts and move their sensitive values to a Secret
+		reason, errMsg := "ArtifactRenderFailed", "Failed to render output artifacts"
 		arts, err := param.RenderArtifacts(artTpls, *tp)
+		if err == nil {
+			if arts, err = c.storeSensitiveArtifacts(as, aIDX, arts); err != nil {
+				reason, errMsg = "ArtifactStoreFailed", "Failed to store sensitive output artifacts"
+			}
+		}
 		var af func(*crv1alpha1.ActionSet) error
 		if err != nil {
 			af = func(ras *crv1alpha1.ActionSet) error {
-				ras.Status.State = crv1alpha1.StateFailed
+				msg := fmt.Sprintf("%s: %s", errMsg, err)
+				now := v1.Now()
+				ras.Status.Actions[aIDX].EndTime = &now
+				setActionError(&ras.Status.Actions[aIDX], msg)
+				setActionSetState(ras.Status, crv1alpha1.StateFailed, reason, msg)
 				return nil
 			}
 		} else {
 			af = func(ras *crv1alpha1.ActionSet) error {
+				now := v1.Now()
 				ras.Status.Actions[aIDX].Artifacts = arts
-				ras.Status.State = crv1alpha1.StateComplete
+				ras.Status.Actions[aIDX].EndTime = &now
+				setActionSetState(ras.Status, crv1alpha1.StateComplete, "Complete", "")
 				return nil
 			}
 		}
@@ -452,17 +857,245 @@ func (c *Controller) runAction(ctx context.Context, as *crv1alpha1.ActionSet, aI
 		}
 		if err != nil {
 			reason := fmt.Sprintf("ActionSetFailed Action: %s", action.Name)
-			msg := "Failed to render output artifacts"
-			c.logAndErrorEvent(msg, reason, err, as, bp)
+			c.logAndErrorEvent(errMsg, reason, err, as, bp)
 			return nil
 		}
+		recordSuccess()
 		return nil
 	})
 	return nil
 }
 
+// actionParams returns the Blueprint of the ActionSet's action and the
+// parameters that its phases are rendered with.
+func (c *Controller) actionParams(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) (*crv1alpha1.Blueprint, *param.TemplateParams, error) {
+	action := as.Spec.Actions[aIDX]
+	bpName := actionBlueprint(as, aIDX)
+	bp, err := c.getBlueprint(as.GetNamespace(), bpName)
+	if err != nil {
+		return nil, nil, err
+	}
+	bpa, ok := bp.Actions[action.Name]
+	if !ok {
+		return nil, nil, errors.Errorf("Action %s not found in blueprint %s", action.Name, bpName)
+	}
+	// Declared options that are not set are passed to the phases with
+	// their default.
+	if action.Options, err = param.ActionOptions(bpa.Options, action.Options); err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid options for action %s of blueprint %s", action.Name, bpName)
+	}
+	if err := c.checkArtifactRefs(as.GetNamespace(), action.Artifacts); err != nil {
+		return nil, nil, err
+	}
+	tp, err := param.New(ctx, c.clientset, c.crClient, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	tp.PodLabels = actionSetPodLabels(as)
+	return bp, tp, nil
+}
+
+// actionTimeout returns the timeout of an action. A timeout set in the
+// ActionSpec takes precedence over the one in the Blueprint.
+func actionTimeout(a crv1alpha1.ActionSpec, bpa *crv1alpha1.BlueprintAction) time.Duration {
+	if a.Timeout != nil {
+		return a.Timeout.Duration
+	}
+	if bpa != nil && bpa.Timeout != nil {
+		return bpa.Timeout.Duration
+	}
+	return 0
+}
+
+// withTimeout is like context.WithTimeout, but a non-positive timeout means
+// the returned context has no deadline.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// phaseStatusFunc returns the status of a phase within an ActionSet.
+type phaseStatusFunc func(*crv1alpha1.ActionSet) *crv1alpha1.Phase
+
+// executePhase runs a single phase with ectx and records its result in the
+// ActionSet status using ctx. An output that contains secret values, or
+// values that sensitive output artifacts refer to, is stored in the Secret
+// outputSecret. It returns true iff the phase completed.
+func (c *Controller) executePhase(ctx, ectx context.Context, as *crv1alpha1.ActionSet, aIDX int, bp *crv1alpha1.Blueprint, tp *param.TemplateParams, p *kanister.Phase, ps phaseStatusFunc, outputSecret string, sensitive param.PhaseOutputs) bool {
+	action := as.Spec.Actions[aIDX]
+	ns, name := as.GetNamespace(), as.GetName()
+	c.logAndSuccessEvent(fmt.Sprintf("Executing phase %s", p.Name()), "Started Phase", as)
+	err := param.InitPhaseParams(ctx, c.clientset, tp, p.Name(), p.Objects())
+	var output map[string]interface{}
+	var msg string
+	var timedOut bool
+	var skip bool
+	// Other phases of the action may update tp while this one executes.
+	ptp := param.PhaseSnapshot(tp)
+	defer redact.Register(param.SecretValues(ptp)...)()
+	if err == nil {
+		if skip, err = p.Skip(ptp); err != nil {
+			msg = fmt.Sprintf("Failed to evaluate phase condition: %#v:", *ps(as))
+		}
+	} else {
+		msg = fmt.Sprintf("Failed to init phase params: %#v:", *ps(as))
+	}
+	if skip {
+		return c.skipPhase(ctx, as, aIDX, bp, p, ps)
+	}
+	if err == nil {
+		pctx, pcancel := withTimeout(ectx, p.Timeout())
+		start := time.Now()
+		output, err = p.ExecWithRetries(pctx, *bp, action.Name, ptp, c.onPhaseAttempt(ctx, as, p.Name(), ps))
+		timedOut = err != nil && pctx.Err() == context.DeadlineExceeded
+		pcancel()
+		metrics.ObservePhase(p.FuncName(), string(phaseState(ctx, err, timedOut)), time.Since(start))
+	}
+	if ctx.Err() != nil {
+		// The ActionSet was cancelled or deleted. Its final state is
+		// recorded by cancelActionSet.
+		return false
+	}
+	var soutput map[string]interface{}
+	var outputRef *crv1alpha1.ObjectReference
+	if err == nil {
+		if soutput, outputRef, err = c.storePhaseOutput(as, outputSecret, output, sensitiveOutputKeys(sensitive, p.Name(), output)); err != nil {
+			msg = fmt.Sprintf("Failed to store phase output: %#v:", *ps(as))
+		}
+	}
+	now := v1.Now()
+	var rf func(*crv1alpha1.ActionSet) error
+	switch {
+	case timedOut:
+		rf = func(ras *crv1alpha1.ActionSet) error {
+			phaseErr := redact.String(fmt.Sprintf("Phase %s timed out: %s", p.Name(), err))
+			ps(ras).State = crv1alpha1.StateTimedOut
+			ps(ras).EndTime = &now
+			ps(ras).Error = redact.String(err.Error())
+			setActionError(&ras.Status.Actions[aIDX], phaseErr)
+			if !ras.Spec.Cancel {
+				setActionSetState(ras.Status, crv1alpha1.StateFailed, "PhaseTimedOut", phaseErr)
+			}
+			return nil
+		}
+	case err != nil:
+		rf = func(ras *crv1alpha1.ActionSet) error {
+			phaseErr := redact.String(fmt.Sprintf("Phase %s failed: %s", p.Name(), err))
+			ps(ras).State = crv1alpha1.StateFailed
+			ps(ras).EndTime = &now
+			ps(ras).Error = redact.String(err.Error())
+			setActionError(&ras.Status.Actions[aIDX], phaseErr)
+			if !ras.Spec.Cancel {
+				setActionSetState(ras.Status, crv1alpha1.StateFailed, "PhaseFailed", phaseErr)
+			}
+			return nil
+		}
+	default:
+		rf = func(ras *crv1alpha1.ActionSet) error {
+			ps(ras).State = crv1alpha1.StateComplete
+			ps(ras).EndTime = &now
+			ps(ras).Output = soutput
+			ps(ras).OutputRef = outputRef
+			return nil
+		}
+	}
+	if rErr := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), ns, name, rf); rErr != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", action.Name)
+		msg := fmt.Sprintf("Failed to update phase: %#v:", *ps(as))
+		c.logAndErrorEvent(msg, reason, rErr, as, bp)
+		return false
+	}
+	if timedOut {
+		reason := fmt.Sprintf("ActionSetTimedOut Action: %s", action.Name)
+		msg = fmt.Sprintf("Phase %s timed out:", p.Name())
+		c.logAndErrorEvent(msg, reason, err, as, bp)
+		return false
+	}
+	if err != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", action.Name)
+		if msg == "" {
+			msg = fmt.Sprintf("Failed to execute phase: %#v:", *ps(as))
+		}
+		c.logAndErrorEvent(msg, reason, err, as, bp)
+		return false
+	}
+	param.UpdatePhaseParams(ctx, tp, p.Name(), output)
+	c.logAndSuccessEvent(fmt.Sprintf("Completed phase %s", p.Name()), "Ended Phase", as)
+	return true
+}
+
+// phaseState returns the state in which the execution of a phase ended.
+func phaseState(ctx context.Context, err error, timedOut bool) crv1alpha1.State {
+	switch {
+	case ctx.Err() != nil:
+		return crv1alpha1.StateCancelled
+	case timedOut:
+		return crv1alpha1.StateTimedOut
+	case err != nil:
+		return crv1alpha1.StateFailed
+	}
+	return crv1alpha1.StateComplete
+}
+
+// setActionEndTime records the end time of an action that did not complete.
+func (c *Controller) setActionEndTime(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) {
+	if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		now := v1.Now()
+		ras.Status.Actions[aIDX].EndTime = &now
+		return nil
+	}); err != nil {
+		log.Errorf("Failed to update end time of action %s: %+v", as.Spec.Actions[aIDX].Name, err)
+	}
+}
+
+// skipPhase records that a phase was not executed because its condition was
+// false. It returns true iff the status was updated.
+func (c *Controller) skipPhase(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int, bp *crv1alpha1.Blueprint, p *kanister.Phase, ps phaseStatusFunc) bool {
+	if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		now := v1.Now()
+		ps(ras).State = crv1alpha1.StateSkipped
+		ps(ras).StartTime, ps(ras).EndTime = &now, &now
+		return nil
+	}); err != nil {
+		reason := fmt.Sprintf("ActionSetFailed Action: %s", as.Spec.Actions[aIDX].Name)
+		msg := fmt.Sprintf("Failed to update phase: %#v:", *ps(as))
+		c.logAndErrorEvent(msg, reason, err, as, bp)
+		return false
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Skipped phase %s", p.Name()), "Skipped Phase", as)
+	return true
+}
+
+// onPhaseAttempt returns a callback that records that a phase is running, its
+// start time and its attempt count in the ActionSet status before each
+// execution.
+func (c *Controller) onPhaseAttempt(ctx context.Context, as *crv1alpha1.ActionSet, phaseName string, ps phaseStatusFunc) func(int) {
+	return func(attempt int) {
+		if attempt > 1 {
+			c.logAndSuccessEvent(fmt.Sprintf("Retrying phase %s, attempt %d", phaseName, attempt), "Retrying Phase", as)
+		}
+		if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+			if ps(ras).StartTime == nil {
+				now := v1.Now()
+				ps(ras).StartTime = &now
+			}
+			ps(ras).State = crv1alpha1.StateRunning
+			ps(ras).Attempts = attempt
+			return nil
+		}); err != nil {
+			log.Errorf("Failed to update attempts for phase %s: %+v", phaseName, err)
+		}
+	}
+}
+
+// logAndErrorEvent logs the error and records it as a warning event of the
+// objects. The values registered with the redact package are removed from
+// both.
 func (c *Controller) logAndErrorEvent(msg, reason string, err error, objects ...runtime.Object) {
-	log.Errorf("%s %+v", msg, err)
+	msg = redact.String(msg)
+	log.Errorf("%s %s", msg, redact.String(fmt.Sprintf("%+v", err)))
 	if len(objects) == 0 {
 		return
 	}
@@ -474,12 +1107,15 @@ func (c *Controller) logAndErrorEvent(msg, reason string, err error, objects ...
 		if _, refErr := reference.GetReference(scheme.Scheme, o); refErr != nil {
 			continue
 		}
-		c.recorder.Event(o, corev1.EventTypeWarning, reason, fmt.Sprintf("%s %s", msg, err))
+		c.recorder.Event(o, corev1.EventTypeWarning, reason, redact.String(fmt.Sprintf("%s %s", msg, err)))
 	}
 
 }
 
+// logAndSuccessEvent logs the message and records it as a normal event of the
+// objects, without the values registered with the redact package.
 func (c *Controller) logAndSuccessEvent(msg, reason string, objects ...runtime.Object) {
+	msg = redact.String(msg)
 	log.Info(msg)
 	if len(objects) == 0 {
 		return
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	. "gopkg.in/check.v1"
+	"gopkg.in/tomb.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,12 +16,14 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	crfake "github.com/kanisterio/kanister/pkg/client/clientset/versioned/fake"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned/scheme"
 	crclientv1alpha1 "github.com/kanisterio/kanister/pkg/client/clientset/versioned/typed/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/eventer"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
 	"github.com/kanisterio/kanister/pkg/poll"
+	"github.com/kanisterio/kanister/pkg/reconcile"
 	"github.com/kanisterio/kanister/pkg/resource"
 	"github.com/kanisterio/kanister/pkg/testutil"
 )
@@ -467,3 +470,272 @@ func (s *ControllerSuite) TestRenderArtifactsFailure(c *C) {
 	err = s.waitOnActionSetState(c, as, crv1alpha1.StateFailed)
 	c.Assert(err, IsNil)
 }
+
+func (s *ControllerSuite) TestPhaseTimeout(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.CancelFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp.Actions["myAction"].Phases[0].Timeout = &metav1.Duration{Duration: time.Second}
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	// Add an actionset that references that blueprint.
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	c.Assert(testutil.CancelFuncOut().Error(), DeepEquals, "context deadline exceeded")
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateFailed)
+	c.Assert(err, IsNil)
+
+	as, err = s.crCli.ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(as.Status.Actions[0].Phases[0].State, Equals, crv1alpha1.StateTimedOut)
+	c.Assert(as.Status.Actions[0].Phases[0].Error, Not(Equals), "")
+	c.Assert(as.Status.Actions[0].Phases[0].StartTime, NotNil)
+	c.Assert(as.Status.Actions[0].Phases[0].EndTime, NotNil)
+	c.Assert(as.Status.Actions[0].Error, Not(Equals), "")
+	c.Assert(as.Status.StartTime, NotNil)
+	c.Assert(as.Status.EndTime, NotNil)
+	var failed bool
+	for _, cond := range as.Status.Conditions {
+		if cond.Type == crv1alpha1.ActionSetConditionFailed {
+			failed = cond.Status == v1.ConditionTrue
+			c.Assert(cond.Reason, Equals, "PhaseTimedOut")
+		}
+	}
+	c.Assert(failed, Equals, true)
+}
+
+func (s *ControllerSuite) TestDeferPhase(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.FailFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp.Actions["myAction"].DeferPhase = &crv1alpha1.BlueprintPhase{
+		Name: "myDeferPhase",
+		Func: testutil.ArgFuncName,
+		Args: map[string]interface{}{
+			"key": "cleanup",
+		},
+	}
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	// Add an actionset that references that blueprint.
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	// The deferred phase runs even though the first phase failed.
+	c.Assert(testutil.FailFuncError().Error(), DeepEquals, "Kanister function failed")
+	c.Assert(testutil.ArgFuncArgs(), DeepEquals, map[string]interface{}{"key": "cleanup"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = poll.Wait(ctx, func(context.Context) (bool, error) {
+		as, err := s.crCli.ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		dp := as.Status.Actions[0].DeferPhase
+		return dp != nil && dp.State == crv1alpha1.StateComplete, nil
+	})
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateFailed)
+	c.Assert(err, IsNil)
+}
+
+func (s *ControllerSuite) TestSkipPhase(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.FailFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp.Actions["myAction"].Phases[0].If = "false"
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	// Add an actionset that references that blueprint.
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateComplete)
+	c.Assert(err, IsNil)
+
+	as, err = s.crCli.ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(as.Status.Actions[0].Phases[0].State, Equals, crv1alpha1.StateSkipped)
+	c.Assert(as.Status.Actions[0].Phases[0].StartTime, NotNil)
+	c.Assert(as.Status.Actions[0].Phases[0].EndTime, NotNil)
+}
+
+func (s *ControllerSuite) TestCancelActionSet(c *C) {
+	for _, tc := range []struct {
+		deferPhase *crv1alpha1.BlueprintPhase
+	}{
+		{},
+		// The deferred phase still runs to clean up.
+		{
+			deferPhase: &crv1alpha1.BlueprintPhase{
+				Name: "myDeferPhase",
+				Func: testutil.ArgFuncName,
+				Args: map[string]interface{}{
+					"key": "cleanup",
+				},
+			},
+		},
+	} {
+		bp := testutil.NewTestBlueprint("Deployment", testutil.CancelFuncName, testutil.FailFuncName)
+		bp = testutil.BlueprintWithConfigMap(bp)
+		bp.Actions["myAction"].DeferPhase = tc.deferPhase
+		bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+		c.Assert(err, IsNil)
+
+		// Add an actionset that references that blueprint.
+		as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+		as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+		as, err = s.crCli.ActionSets(s.namespace).Create(as)
+		c.Assert(err, IsNil)
+
+		err = s.waitOnActionSetState(c, as, crv1alpha1.StateRunning)
+		c.Assert(err, IsNil)
+
+		err = reconcile.ActionSet(context.Background(), s.crCli, as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+			ras.Spec.Cancel = true
+			return nil
+		})
+		c.Assert(err, IsNil)
+		c.Assert(testutil.CancelFuncOut().Error(), DeepEquals, "context canceled")
+		if tc.deferPhase != nil {
+			c.Assert(testutil.ArgFuncArgs(), DeepEquals, map[string]interface{}{"key": "cleanup"})
+		}
+
+		err = s.waitOnActionSetState(c, as, crv1alpha1.StateCancelled)
+		c.Assert(err, IsNil)
+
+		// The ActionSet and its status are kept.
+		as, err = s.crCli.ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+		c.Assert(err, IsNil)
+		c.Assert(as.Status.Actions[0].Phases[0].State, Equals, crv1alpha1.StateCancelled)
+		c.Assert(as.Status.Actions[0].Phases[1].State, Equals, crv1alpha1.StatePending)
+		if tc.deferPhase != nil {
+			c.Assert(as.Status.Actions[0].DeferPhase.State, Equals, crv1alpha1.StateComplete)
+		}
+	}
+}
+
+func (s *ControllerSuite) TestResumeActionSet(c *C) {
+	bp := testutil.NewTestBlueprint("Deployment", testutil.OutputFuncName, testutil.FailFuncName)
+	bp = testutil.BlueprintWithConfigMap(bp)
+	bp.Actions["myAction"].Phases[0].Args = map[string]interface{}{
+		"key": "myValue",
+	}
+	bp, err := s.crCli.Blueprints(s.namespace).Create(bp)
+	c.Assert(err, IsNil)
+
+	// Add an actionset that references that blueprint.
+	as := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+	as = testutil.ActionSetWithConfigMap(as, s.confimap.GetName())
+	as, err = s.crCli.ActionSets(s.namespace).Create(as)
+	c.Assert(err, IsNil)
+
+	c.Assert(testutil.OutputFuncOut(), DeepEquals, map[string]interface{}{"key": "myValue"})
+	c.Assert(testutil.FailFuncError().Error(), DeepEquals, "Kanister function failed")
+	err = s.waitOnActionSetState(c, as, crv1alpha1.StateFailed)
+	c.Assert(err, IsNil)
+
+	// Resume the failed actionset. The first phase is not executed again.
+	ras := testutil.NewTestActionSet(s.namespace, bp.GetName(), "Deployment", s.deployment.GetName(), s.namespace)
+	ras = testutil.ActionSetWithConfigMap(ras, s.confimap.GetName())
+	ras.SetAnnotations(map[string]string{crv1alpha1.ResumeFromAnnotation: as.GetName()})
+	ras, err = s.crCli.ActionSets(s.namespace).Create(ras)
+	c.Assert(err, IsNil)
+
+	c.Assert(testutil.FailFuncError().Error(), DeepEquals, "Kanister function failed")
+	err = s.waitOnActionSetState(c, ras, crv1alpha1.StateFailed)
+	c.Assert(err, IsNil)
+
+	ras, err = s.crCli.ActionSets(ras.GetNamespace()).Get(ras.GetName(), metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(ras.Status.Actions[0].Phases[0].State, Equals, crv1alpha1.StateComplete)
+	c.Assert(ras.Status.Actions[0].Phases[0].Output, DeepEquals, map[string]interface{}{"key": "myValue"})
+	c.Assert(ras.Status.Actions[0].Phases[1].State, Equals, crv1alpha1.StateFailed)
+}
+
+type CancelSuite struct{}
+
+var _ = Suite(&CancelSuite{})
+
+func (s *CancelSuite) TestDeleteActionSet(c *C) {
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister"},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: []crv1alpha1.ActionSpec{{Name: "backup"}},
+		},
+	}
+	ctrl := &Controller{}
+	t, ctx := tomb.WithContext(context.Background())
+	t.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	ctrl.actionSetTombMap.Store(actionTombKey(as, 0), t)
+
+	// The actions are stopped with a reason that tells them apart from a
+	// cancel, so that their deferred phases are not run.
+	err := ctrl.onDeleteActionSet(as)
+	c.Assert(err, IsNil)
+	c.Assert(t.Wait(), Equals, errActionSetDeleted)
+	_, ok := ctrl.actionSetTombMap.Load(actionTombKey(as, 0))
+	c.Assert(ok, Equals, false)
+}
+
+func (s *CancelSuite) TestCancelActionSetOnce(c *C) {
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister"},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: []crv1alpha1.ActionSpec{{Name: "backup", Object: crv1alpha1.ObjectReference{Kind: "Deployment"}}},
+			Cancel:  true,
+		},
+		Status: &crv1alpha1.ActionSetStatus{
+			State: crv1alpha1.StateRunning,
+			Actions: []crv1alpha1.ActionStatus{{
+				Name:   "backup",
+				Phases: []crv1alpha1.Phase{{Name: "dump", State: crv1alpha1.StateRunning}},
+			}},
+		},
+	}
+	crCli := crfake.NewSimpleClientset(as)
+	ctrl := &Controller{crClient: crCli, recorder: record.NewFakeRecorder(10)}
+	release := make(chan struct{})
+	t := &tomb.Tomb{}
+	t.Go(func() error {
+		<-release
+		return nil
+	})
+	ctrl.actionSetTombMap.Store(actionTombKey(as, 0), t)
+
+	err := ctrl.cancelActionSet(as)
+	c.Assert(err, IsNil)
+	// Updates made while the action stops do not cancel the ActionSet
+	// before it has stopped.
+	err = ctrl.cancelActionSet(as)
+	c.Assert(err, IsNil)
+	time.Sleep(100 * time.Millisecond)
+	ras, err := crCli.CrV1alpha1().ActionSets("kanister").Get("backup", metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(ras.Status.State, Equals, crv1alpha1.StateRunning)
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = poll.Wait(ctx, func(context.Context) (bool, error) {
+		ras, err := crCli.CrV1alpha1().ActionSets("kanister").Get("backup", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ras.Status.State == crv1alpha1.StateCancelled, nil
+	})
+	c.Assert(err, IsNil)
+}
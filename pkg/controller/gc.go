@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/location"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+const (
+	// actionSetGCPeriod is how often finished ActionSets are collected.
+	actionSetGCPeriod = time.Minute
+	// actionSetArchiveDir is where ActionSets are archived, relative to the
+	// prefix of the location of their profile.
+	actionSetArchiveDir = "kanister/actionsets"
+)
+
+// runActionSetGC deletes the finished ActionSets in the namespace, or in all
+// namespaces, that have expired until the context is cancelled.
+func (c *Controller) runActionSetGC(ctx context.Context, namespace string) {
+	if c.opts.ActionSetTTL == 0 && c.opts.MaxActionSets == 0 {
+		log.Infof("Finished ActionSets are only deleted if they set a TTL")
+	}
+	wait.Until(func() {
+		asl, err := c.crClient.CrV1alpha1().ActionSets(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.Errorf("Failed to list ActionSets: %+v", err)
+			return
+		}
+		rpl, err := c.crClient.CrV1alpha1().RetentionPolicies(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.Errorf("Failed to list RetentionPolicies: %+v", err)
+			return
+		}
+		// The maximum number of finished ActionSets applies to each
+		// namespace, even if they are listed from all namespaces.
+		byNamespace := make(map[string][]*crv1alpha1.ActionSet)
+		for _, as := range asl.Items {
+			byNamespace[as.GetNamespace()] = append(byNamespace[as.GetNamespace()], as)
+		}
+		rpsByNamespace := make(map[string][]*crv1alpha1.RetentionPolicy)
+		for _, rp := range rpl.Items {
+			rpsByNamespace[rp.GetNamespace()] = append(rpsByNamespace[rp.GetNamespace()], rp)
+		}
+		for ns, ass := range byNamespace {
+			retained := retainedActionSets(rpsByNamespace[ns], ass)
+			for _, as := range expiredActionSets(ass, retained, c.opts, time.Now()) {
+				if err := c.deleteFinishedActionSet(ctx, as); err != nil {
+					log.Errorf("Failed to delete finished ActionSet %s: %+v", as.GetName(), err)
+				}
+			}
+		}
+	}, actionSetGCPeriod, ctx.Done())
+}
+
+// expiredActionSets returns the finished ActionSets that are older than their
+// TTL or that exceed the maximum number of ActionSets, oldest first. The
+// default TTL and the maximum only apply to ActionSets that are not owned by
+// another object, such as a Schedule or a backup that is being deleted, since
+// their owner manages them. They do not apply either to the retained
+// ActionSets, which are named in retained, or to ActionSets whose sensitive
+// output artifacts or phase outputs are stored in Secrets that they own, since
+// deleting them would leave their backups behind and prevent resuming them.
+func expiredActionSets(ass []*crv1alpha1.ActionSet, retained map[string]bool, opts Options, now time.Time) []*crv1alpha1.ActionSet {
+	var finished []*crv1alpha1.ActionSet
+	for _, as := range ass {
+		if actionSetFinished(as) {
+			finished = append(finished, as)
+		}
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finishTime(finished[i]).Before(finishTime(finished[j]))
+	})
+	var expired, kept []*crv1alpha1.ActionSet
+	for _, as := range finished {
+		managed := len(as.GetOwnerReferences()) > 0 || retained[as.GetName()] || ownsSecrets(as)
+		ttl, ok := opts.ActionSetTTL, opts.ActionSetTTL > 0 && !managed
+		if as.Spec != nil && as.Spec.TTLSecondsAfterFinished != nil {
+			ttl, ok = time.Duration(*as.Spec.TTLSecondsAfterFinished)*time.Second, true
+		}
+		switch {
+		case ok && !now.Before(finishTime(as).Add(ttl)):
+			expired = append(expired, as)
+		case !managed:
+			kept = append(kept, as)
+		}
+	}
+	if opts.MaxActionSets > 0 && len(kept) > opts.MaxActionSets {
+		expired = append(expired, kept[:len(kept)-opts.MaxActionSets]...)
+	}
+	return expired
+}
+
+// retainedActionSets returns the names of the backup ActionSets of the
+// namespace that the RetentionPolicies apply to. The policies delete them
+// along with their artifacts once they expire.
+func retainedActionSets(rps []*crv1alpha1.RetentionPolicy, ass []*crv1alpha1.ActionSet) map[string]bool {
+	retained := make(map[string]bool)
+	for _, rp := range rps {
+		if rp.Spec == nil {
+			continue
+		}
+		sel := labels.Everything()
+		if rp.Spec.Selector != nil {
+			var err error
+			if sel, err = metav1.LabelSelectorAsSelector(rp.Spec.Selector); err != nil {
+				continue
+			}
+		}
+		var selected []*crv1alpha1.ActionSet
+		for _, as := range ass {
+			if sel.Matches(labels.Set(as.GetLabels())) {
+				selected = append(selected, as)
+			}
+		}
+		for _, as := range backupActionSets(rp.Spec.Action, selected) {
+			retained[as.GetName()] = true
+		}
+	}
+	return retained
+}
+
+// ownsSecrets returns whether the sensitive output artifacts or the phase
+// outputs of the ActionSet refer to a Secret that it owns.
+func ownsSecrets(as *crv1alpha1.ActionSet) bool {
+	if as.Status == nil {
+		return false
+	}
+	for _, a := range as.Status.Actions {
+		for _, art := range a.Artifacts {
+			if art.SecretRef != nil {
+				return true
+			}
+		}
+		for _, p := range a.Phases {
+			if p.OutputRef != nil {
+				return true
+			}
+		}
+		if a.DeferPhase != nil && a.DeferPhase.OutputRef != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// actionSetFinished returns whether the ActionSet completed, failed or was
+// cancelled.
+func actionSetFinished(as *crv1alpha1.ActionSet) bool {
+	if as.Status == nil {
+		return false
+	}
+	switch as.Status.State {
+	case crv1alpha1.StateComplete, crv1alpha1.StateFailed, crv1alpha1.StateCancelled:
+		return true
+	}
+	return false
+}
+
+// finishTime returns when the ActionSet completed, failed or was cancelled.
+func finishTime(as *crv1alpha1.ActionSet) time.Time {
+	if as.Status != nil && as.Status.EndTime != nil {
+		return as.Status.EndTime.Time
+	}
+	return as.GetCreationTimestamp().Time
+}
+
+func (c *Controller) deleteFinishedActionSet(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	if c.opts.ArchiveActionSets {
+		if err := c.archiveActionSet(ctx, as); err != nil {
+			return err
+		}
+	}
+	err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Delete(as.GetName(), nil)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	log.Infof("Deleted finished ActionSet %s", as.GetName())
+	return nil
+}
+
+// archiveActionSet writes the ActionSet, including its status, as JSON to
+// the location of the first profile of its actions. ActionSets without a
+// profile are not archived.
+func (c *Controller) archiveActionSet(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	var ref *crv1alpha1.ObjectReference
+	if as.Spec != nil {
+		for _, a := range as.Spec.Actions {
+			if a.Profile != nil {
+				ref = a.Profile
+				break
+			}
+		}
+	}
+	if ref == nil {
+		log.Infof("Not archiving ActionSet %s since it has no profile", as.GetName())
+		return nil
+	}
+	p, err := param.FetchProfile(ctx, c.clientset, c.crClient, ref)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to fetch the profile to archive ActionSet %s", as.GetName())
+	}
+	as = as.DeepCopy()
+	as.TypeMeta = metav1.TypeMeta{
+		Kind:       crv1alpha1.ActionSetResource.Kind,
+		APIVersion: crv1alpha1.SchemeGroupVersion.String(),
+	}
+	buf, err := json.Marshal(as)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := location.Write(ctx, bytes.NewReader(buf), *p, actionSetArchivePath(as)); err != nil {
+		return errors.Wrapf(err, "Failed to archive ActionSet %s", as.GetName())
+	}
+	return nil
+}
+
+// actionSetArchivePath returns where the ActionSet is archived, relative to
+// the prefix of the location of its profile.
+func actionSetArchivePath(as *crv1alpha1.ActionSet) string {
+	return path.Join(actionSetArchiveDir, as.GetNamespace(), as.GetName()+".json")
+}
@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type GCSuite struct{}
+
+var _ = Suite(&GCSuite{})
+
+func (s *GCSuite) TestExpiredActionSets(c *C) {
+	now := time.Date(2019, time.July, 2, 0, 0, 0, 0, time.UTC)
+	newAS := func(name string, state crv1alpha1.State, age time.Duration, ttl *int32, owned bool) *crv1alpha1.ActionSet {
+		as := &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       &crv1alpha1.ActionSetSpec{TTLSecondsAfterFinished: ttl},
+			Status: &crv1alpha1.ActionSetStatus{
+				State:   state,
+				EndTime: &metav1.Time{Time: now.Add(-age)},
+			},
+		}
+		if owned {
+			as.OwnerReferences = []metav1.OwnerReference{{Kind: crv1alpha1.ScheduleResource.Kind, Name: "nightly"}}
+		}
+		return as
+	}
+	ttl := func(s int32) *int32 { return &s }
+	ass := []*crv1alpha1.ActionSet{
+		newAS("complete-1h", crv1alpha1.StateComplete, time.Hour, nil, false),
+		newAS("failed-3h", crv1alpha1.StateFailed, 3*time.Hour, nil, false),
+		newAS("running-5h", crv1alpha1.StateRunning, 5*time.Hour, ttl(0), false),
+		newAS("cancelled-2h", crv1alpha1.StateCancelled, 2*time.Hour, nil, false),
+		newAS("complete-30m-ttl-10m", crv1alpha1.StateComplete, 30*time.Minute, ttl(600), false),
+		newAS("complete-4h-ttl-1d", crv1alpha1.StateComplete, 4*time.Hour, ttl(86400), false),
+		newAS("owned-6h", crv1alpha1.StateComplete, 6*time.Hour, nil, true),
+		newAS("owned-5h-ttl-1h", crv1alpha1.StateComplete, 5*time.Hour, ttl(3600), true),
+	}
+	for _, tc := range []struct {
+		opts    Options
+		expired []string
+	}{
+		{
+			opts:    Options{},
+			expired: []string{"owned-5h-ttl-1h", "complete-30m-ttl-10m"},
+		},
+		{
+			opts:    Options{ActionSetTTL: 2 * time.Hour},
+			expired: []string{"owned-5h-ttl-1h", "failed-3h", "cancelled-2h", "complete-30m-ttl-10m"},
+		},
+		{
+			opts:    Options{MaxActionSets: 2},
+			expired: []string{"owned-5h-ttl-1h", "complete-30m-ttl-10m", "complete-4h-ttl-1d", "failed-3h"},
+		},
+		{
+			opts:    Options{ActionSetTTL: 150 * time.Minute, MaxActionSets: 1},
+			expired: []string{"owned-5h-ttl-1h", "failed-3h", "complete-30m-ttl-10m", "complete-4h-ttl-1d", "cancelled-2h"},
+		},
+	} {
+		expired := expiredActionSets(ass, nil, tc.opts, now)
+		var names []string
+		for _, as := range expired {
+			names = append(names, as.Name)
+		}
+		c.Check(names, DeepEquals, tc.expired, Commentf("%#v", tc.opts))
+	}
+}
+
+func (s *GCSuite) TestExpiredRetainedActionSets(c *C) {
+	now := time.Date(2019, time.July, 2, 0, 0, 0, 0, time.UTC)
+	newAS := func(name, action string, labels map[string]string, age time.Duration) *crv1alpha1.ActionSet {
+		return &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Spec: &crv1alpha1.ActionSetSpec{
+				Actions: []crv1alpha1.ActionSpec{{Name: action}},
+			},
+			Status: &crv1alpha1.ActionSetStatus{
+				State:   crv1alpha1.StateComplete,
+				EndTime: &metav1.Time{Time: now.Add(-age)},
+				Actions: []crv1alpha1.ActionStatus{{Name: action}},
+			},
+		}
+	}
+	app := map[string]string{"app": "mysql"}
+	backup := newAS("backup-4h", "backup", app, 4*time.Hour)
+	otherBackup := newAS("backup-3h-other", "backup", map[string]string{"app": "pg"}, 3*time.Hour)
+	restore := newAS("restore-2h", "restore", app, 2*time.Hour)
+	sensitive := newAS("backup-1h-sensitive", "backup", nil, time.Hour)
+	sensitive.Status.Actions[0].Artifacts = map[string]crv1alpha1.Artifact{
+		"cloudObject": {
+			KeyValue:  map[string]string{"path": "/backups/1"},
+			SecretRef: &crv1alpha1.ObjectReference{Kind: "Secret", Name: "backup-1h-sensitive-artifacts-0"},
+		},
+	}
+	failed := newAS("backup-30m-failed", "backup", nil, 30*time.Minute)
+	failed.Status.State = crv1alpha1.StateFailed
+	failed.Status.Actions[0].Phases = []crv1alpha1.Phase{
+		{
+			Name:      "dump",
+			State:     crv1alpha1.StateComplete,
+			OutputRef: &crv1alpha1.ObjectReference{Kind: "Secret", Name: "backup-30m-failed-output-0-0"},
+		},
+		{Name: "upload", State: crv1alpha1.StateFailed},
+	}
+	ass := []*crv1alpha1.ActionSet{backup, otherBackup, restore, sensitive, failed}
+	rps := []*crv1alpha1.RetentionPolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql"},
+			Spec: &crv1alpha1.RetentionPolicySpec{
+				Action:   "backup",
+				Selector: &metav1.LabelSelector{MatchLabels: app},
+			},
+		},
+	}
+	retained := retainedActionSets(rps, ass)
+	c.Assert(retained, DeepEquals, map[string]bool{"backup-4h": true})
+
+	// The backups that a RetentionPolicy deletes and those that own the
+	// Secret of their artifacts or phase outputs are neither expired by the default TTL nor
+	// counted towards the maximum.
+	for _, tc := range []struct {
+		opts    Options
+		expired []string
+	}{
+		{
+			opts:    Options{ActionSetTTL: 30 * time.Minute},
+			expired: []string{"backup-3h-other", "restore-2h"},
+		},
+		{
+			opts:    Options{MaxActionSets: 1},
+			expired: []string{"backup-3h-other"},
+		},
+	} {
+		expired := expiredActionSets(ass, retained, tc.opts, now)
+		var names []string
+		for _, as := range expired {
+			names = append(names, as.Name)
+		}
+		c.Check(names, DeepEquals, tc.expired, Commentf("%#v", tc.opts))
+	}
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/metrics"
+)
+
+// stateCounter returns a metrics.StateCounter that counts the watched
+// ActionSets and their phases by state. They are counted from the statuses
+// that the watch callbacks observed, so that collecting the metrics does not
+// list the ActionSets.
+func (c *Controller) stateCounter() metrics.StateCounter {
+	return func() (map[string]int, map[string]int, error) {
+		var ass []*crv1alpha1.ActionSet
+		c.actionSetStatuses.Range(func(_, v interface{}) bool {
+			if s, ok := v.(*crv1alpha1.ActionSetStatus); ok {
+				ass = append(ass, &crv1alpha1.ActionSet{Status: s})
+			}
+			return true
+		})
+		actionSets, phases := countStates(ass)
+		return actionSets, phases, nil
+	}
+}
+
+// observeActionSet records the status of an added or updated ActionSet.
+func (c *Controller) observeActionSet(as *crv1alpha1.ActionSet) {
+	c.actionSetStatuses.Store(as.GetNamespace()+"/"+as.GetName(), as.Status.DeepCopy())
+}
+
+// forgetActionSet removes the status of a deleted ActionSet.
+func (c *Controller) forgetActionSet(as *crv1alpha1.ActionSet) {
+	c.actionSetStatuses.Delete(as.GetNamespace() + "/" + as.GetName())
+}
+
+// countStates returns the number of ActionSets and of their phases by state.
+// ActionSets without a status and phases without a state are pending.
+func countStates(ass []*crv1alpha1.ActionSet) (map[string]int, map[string]int) {
+	actionSets := make(map[string]int)
+	phases := make(map[string]int)
+	for _, as := range ass {
+		if as.Status == nil {
+			actionSets[string(crv1alpha1.StatePending)]++
+			continue
+		}
+		actionSets[string(stateOrPending(as.Status.State))]++
+		for _, a := range as.Status.Actions {
+			for _, p := range a.Phases {
+				phases[string(stateOrPending(p.State))]++
+			}
+			if a.DeferPhase != nil {
+				phases[string(stateOrPending(a.DeferPhase.State))]++
+			}
+		}
+	}
+	return actionSets, phases
+}
+
+func stateOrPending(s crv1alpha1.State) crv1alpha1.State {
+	if s == "" {
+		return crv1alpha1.StatePending
+	}
+	return s
+}
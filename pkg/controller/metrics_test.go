@@ -0,0 +1,74 @@
+package controller
+
+import (
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type MetricsSuite struct{}
+
+var _ = Suite(&MetricsSuite{})
+
+func (s *MetricsSuite) TestCountStates(c *C) {
+	ass := []*crv1alpha1.ActionSet{
+		{},
+		{
+			Status: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					{
+						Phases: []crv1alpha1.Phase{
+							{State: crv1alpha1.StateComplete},
+							{State: crv1alpha1.StateRunning},
+							{},
+						},
+						DeferPhase: &crv1alpha1.Phase{},
+					},
+				},
+			},
+		},
+		{
+			Status: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateComplete,
+				Actions: []crv1alpha1.ActionStatus{
+					{
+						Phases: []crv1alpha1.Phase{
+							{State: crv1alpha1.StateComplete},
+							{State: crv1alpha1.StateSkipped},
+						},
+					},
+				},
+			},
+		},
+	}
+	actionSets, phases := countStates(ass)
+	c.Assert(actionSets, DeepEquals, map[string]int{"pending": 1, "running": 1, "complete": 1})
+	c.Assert(phases, DeepEquals, map[string]int{"pending": 2, "running": 1, "complete": 2, "skipped": 1})
+}
+
+func (s *MetricsSuite) TestStateCounter(c *C) {
+	ctrl := &Controller{}
+	newAS := func(ns, name string, state crv1alpha1.State) *crv1alpha1.ActionSet {
+		return &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Status:     &crv1alpha1.ActionSetStatus{State: state},
+		}
+	}
+	ctrl.observeActionSet(&crv1alpha1.ActionSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "backup"}})
+	ctrl.observeActionSet(newAS("ns2", "backup", crv1alpha1.StateRunning))
+	ctrl.observeActionSet(newAS("ns2", "restore", crv1alpha1.StateRunning))
+	count := ctrl.stateCounter()
+	actionSets, _, err := count()
+	c.Assert(err, IsNil)
+	c.Assert(actionSets, DeepEquals, map[string]int{"pending": 1, "running": 2})
+
+	// The last observed status of each ActionSet is counted until it is
+	// deleted.
+	ctrl.observeActionSet(newAS("ns1", "backup", crv1alpha1.StateComplete))
+	ctrl.forgetActionSet(newAS("ns2", "restore", crv1alpha1.StateRunning))
+	actionSets, _, err = count()
+	c.Assert(err, IsNil)
+	c.Assert(actionSets, DeepEquals, map[string]int{"complete": 1, "running": 1})
+}
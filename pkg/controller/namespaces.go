@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// watchedNamespaces returns the distinct namespaces, or only
+// metav1.NamespaceAll if they include it.
+func watchedNamespaces(namespaces []string) []string {
+	seen := make(map[string]bool, len(namespaces))
+	var nss []string
+	for _, ns := range namespaces {
+		if ns == metav1.NamespaceAll {
+			return []string{metav1.NamespaceAll}
+		}
+		if !seen[ns] {
+			seen[ns] = true
+			nss = append(nss, ns)
+		}
+	}
+	return nss
+}
+
+// namespaceName describes the namespace in messages.
+func namespaceName(namespace string) string {
+	if namespace == metav1.NamespaceAll {
+		return "all namespaces"
+	}
+	return fmt.Sprintf("namespace %s", namespace)
+}
+
+// blueprintRef returns the namespace and the name of the Blueprint that the
+// action of an ActionSet in the namespace refers to. The Blueprint is in the
+// namespace of the ActionSet, unless ref is <namespace>/<name>.
+func (c *Controller) blueprintRef(namespace, ref string) (string, string, error) {
+	i := strings.Index(ref, "/")
+	if i < 0 {
+		return namespace, ref, nil
+	}
+	ns, name := ref[:i], ref[i+1:]
+	if err := c.checkCrossNamespaceRef(namespace, ns); err != nil {
+		return "", "", errors.Wrapf(err, "Cannot use blueprint %s", ref)
+	}
+	return ns, name, nil
+}
+
+// checkArtifactRefs returns an error if the Secrets that the artifacts of an
+// action of an ActionSet in the namespace refer to are in a namespace that it
+// may not reference.
+func (c *Controller) checkArtifactRefs(namespace string, arts map[string]crv1alpha1.Artifact) error {
+	for name, a := range arts {
+		if a.SecretRef == nil {
+			continue
+		}
+		if err := c.checkCrossNamespaceRef(namespace, a.SecretRef.Namespace); err != nil {
+			return errors.Wrapf(err, "Cannot use the secret of artifact %s", name)
+		}
+	}
+	return nil
+}
+
+// checkCrossNamespaceRef returns an error if the ActionSets of the namespace
+// cannot use the Blueprints and Profiles of refNamespace. They can use those
+// of their own namespace and of the namespaces in the allow-list.
+func (c *Controller) checkCrossNamespaceRef(namespace, refNamespace string) error {
+	if refNamespace == "" || refNamespace == namespace {
+		return nil
+	}
+	for _, ns := range c.opts.CrossNamespaceAllowList {
+		if ns == refNamespace {
+			return nil
+		}
+	}
+	return errors.Errorf("Namespace %s is not allowed to be referenced by the ActionSets of namespace %s", refNamespace, namespace)
+}
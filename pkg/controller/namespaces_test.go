@@ -0,0 +1,89 @@
+package controller
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/tomb.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type NamespacesSuite struct{}
+
+var _ = Suite(&NamespacesSuite{})
+
+func (s *NamespacesSuite) TestWatchedNamespaces(c *C) {
+	c.Assert(watchedNamespaces([]string{"ns1", "ns2", "ns1"}), DeepEquals, []string{"ns1", "ns2"})
+	c.Assert(watchedNamespaces([]string{"ns1", metav1.NamespaceAll}), DeepEquals, []string{metav1.NamespaceAll})
+}
+
+func (s *NamespacesSuite) TestBlueprintRef(c *C) {
+	ctrl := &Controller{opts: Options{CrossNamespaceAllowList: []string{"kanister"}}}
+	for _, tc := range []struct {
+		ref     string
+		ns      string
+		name    string
+		checker Checker
+	}{
+		{"mysql-blueprint", "app", "mysql-blueprint", IsNil},
+		{"app/mysql-blueprint", "app", "mysql-blueprint", IsNil},
+		{"kanister/mysql-blueprint", "kanister", "mysql-blueprint", IsNil},
+		{"other/mysql-blueprint", "", "", NotNil},
+	} {
+		ns, name, err := ctrl.blueprintRef("app", tc.ref)
+		c.Check(err, tc.checker, Commentf("%s", tc.ref))
+		c.Check(ns, Equals, tc.ns)
+		c.Check(name, Equals, tc.name)
+	}
+}
+
+func (s *NamespacesSuite) TestCheckCrossNamespaceRef(c *C) {
+	ctrl := &Controller{opts: Options{CrossNamespaceAllowList: []string{"kanister"}}}
+	c.Assert(ctrl.checkCrossNamespaceRef("app", ""), IsNil)
+	c.Assert(ctrl.checkCrossNamespaceRef("app", "app"), IsNil)
+	c.Assert(ctrl.checkCrossNamespaceRef("app", "kanister"), IsNil)
+	c.Assert(ctrl.checkCrossNamespaceRef("app", "other"), NotNil)
+	c.Assert((&Controller{}).checkCrossNamespaceRef("app", "kanister"), NotNil)
+}
+
+func (s *NamespacesSuite) TestCheckArtifactRefs(c *C) {
+	ctrl := &Controller{opts: Options{CrossNamespaceAllowList: []string{"kanister"}}}
+	arts := func(ns string) map[string]crv1alpha1.Artifact {
+		return map[string]crv1alpha1.Artifact{
+			"cloudObject": {
+				KeyValue:  map[string]string{"path": "/backups/1"},
+				Sensitive: []string{"encryptionKey"},
+				SecretRef: &crv1alpha1.ObjectReference{Kind: "Secret", Name: "backup-artifacts-0", Namespace: ns},
+			},
+			"manifest": {KeyValue: map[string]string{"path": "/manifests/1"}},
+		}
+	}
+	c.Assert(ctrl.checkArtifactRefs("app", arts("app")), IsNil)
+	c.Assert(ctrl.checkArtifactRefs("app", arts("kanister")), IsNil)
+	c.Assert(ctrl.checkArtifactRefs("app", arts("kube-system")), NotNil)
+}
+
+func (s *NamespacesSuite) TestActionTombsByNamespace(c *C) {
+	newActionSet := func(namespace string) *crv1alpha1.ActionSet {
+		return &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: namespace},
+			Spec: &crv1alpha1.ActionSetSpec{
+				Actions: []crv1alpha1.ActionSpec{{Name: "backup"}},
+			},
+		}
+	}
+	as1, as2 := newActionSet("ns1"), newActionSet("ns2")
+	ctrl := &Controller{}
+	t1, t2 := &tomb.Tomb{}, &tomb.Tomb{}
+	ctrl.actionSetTombMap.Store(actionTombKey(as1, 0), t1)
+	ctrl.actionSetTombMap.Store(actionTombKey(as2, 0), t2)
+	c.Assert(ctrl.executesActionSet(as1), Equals, true)
+	c.Assert(ctrl.executesActionSet(as2), Equals, true)
+
+	// Removing the tombs of an ActionSet keeps those of the ActionSet with
+	// the same name in another namespace.
+	c.Assert(ctrl.removeActionTombs(as1), DeepEquals, []*tomb.Tomb{t1})
+	c.Assert(ctrl.executesActionSet(as1), Equals, false)
+	c.Assert(ctrl.executesActionSet(as2), Equals, true)
+	c.Assert(ctrl.removeActionTombs(as2), DeepEquals, []*tomb.Tomb{t2})
+}
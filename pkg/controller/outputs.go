@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/redact"
+)
+
+// phaseOutputSecretKey is the key of the output in the Secret of a phase.
+const phaseOutputSecretKey = "output"
+
+// deferPhaseIndex names the deferred phase of an action in the name of the
+// Secret of its output.
+const deferPhaseIndex = "defer"
+
+// phaseOutputSecretName returns the name of the Secret that stores the output
+// of a phase of an action of the ActionSet. The phase is named by its index,
+// or deferPhaseIndex.
+func phaseOutputSecretName(as *crv1alpha1.ActionSet, aIDX int, phase string) string {
+	return fmt.Sprintf("%s-output-%d-%s", as.GetName(), aIDX, phase)
+}
+
+// storePhaseOutput returns the output of a phase as it is recorded in the
+// status of the ActionSet, without the secret values and the values of the
+// sensitive keys. If the output has any, it is stored in the named Secret,
+// which the returned reference refers to, so that it can be used when the
+// ActionSet is resumed or restarted.
+func (c *Controller) storePhaseOutput(as *crv1alpha1.ActionSet, name string, output map[string]interface{}, sensitive []string) (map[string]interface{}, *crv1alpha1.ObjectReference, error) {
+	if len(output) == 0 {
+		return output, nil, nil
+	}
+	routput := make(map[string]interface{}, len(output))
+	for k, v := range redact.Map(output) {
+		routput[k] = v
+	}
+	for _, k := range sensitive {
+		if _, ok := routput[k]; ok {
+			routput[k] = redact.Mask
+		}
+	}
+	if reflect.DeepEqual(routput, output) {
+		return output, nil, nil
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to encode phase output")
+	}
+	ref := actionSetSecretRef(as, name)
+	if err := c.storeActionSetSecret(as, ref, map[string][]byte{phaseOutputSecretKey: data}); err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to store phase output")
+	}
+	return routput, &ref, nil
+}
+
+// sensitiveOutputKeys returns the keys of the output of the phase that the
+// sensitive keys of the output artifacts refer to.
+func sensitiveOutputKeys(refs param.PhaseOutputs, phase string, output map[string]interface{}) []string {
+	keys, ok := refs[phase]
+	if !ok || keys != nil {
+		return keys
+	}
+	// The artifacts refer to the whole output.
+	keys = make([]string, 0, len(output))
+	for k := range output {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// phaseOutput returns the output of a completed phase. It is read from the
+// Secret that the phase refers to if secret values were removed from its
+// status.
+func (c *Controller) phaseOutput(ps crv1alpha1.Phase) (map[string]interface{}, error) {
+	if ps.OutputRef == nil {
+		return ps.Output, nil
+	}
+	s, err := c.clientset.CoreV1().Secrets(ps.OutputRef.Namespace).Get(ps.OutputRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read the output of phase %s from secret '%s:%s'", ps.Name, ps.OutputRef.Namespace, ps.OutputRef.Name)
+	}
+	// Numbers are decoded as they were written, so that large integers are
+	// not rendered in exponent notation.
+	dec := json.NewDecoder(bytes.NewReader(s.Data[phaseOutputSecretKey]))
+	dec.UseNumber()
+	var output map[string]interface{}
+	if err := dec.Decode(&output); err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode the output of phase %s", ps.Name)
+	}
+	return output, nil
+}
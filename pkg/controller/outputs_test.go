@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/redact"
+)
+
+type OutputsSuite struct{}
+
+var _ = Suite(&OutputsSuite{})
+
+func (s *OutputsSuite) TestStorePhaseOutput(c *C) {
+	ctrl := &Controller{clientset: fake.NewSimpleClientset()}
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister"},
+	}
+	name := phaseOutputSecretName(as, 0, "1")
+	c.Assert(name, Equals, "backup-output-0-1")
+
+	// An output without secret values is recorded as is.
+	output := map[string]interface{}{"id": "snapshot-1"}
+	soutput, ref, err := ctrl.storePhaseOutput(as, name, output, nil)
+	c.Assert(err, IsNil)
+	c.Assert(ref, IsNil)
+	c.Assert(soutput, DeepEquals, output)
+	_, err = ctrl.clientset.CoreV1().Secrets("kanister").Get(name, metav1.GetOptions{})
+	c.Assert(err, NotNil)
+
+	// The secret values are redacted from the status, and the output is
+	// read back from the Secret.
+	defer redact.Register("s3cr3t-key")()
+	output = map[string]interface{}{"id": "snapshot-1", "key": "s3cr3t-key"}
+	soutput, ref, err = ctrl.storePhaseOutput(as, name, output, nil)
+	c.Assert(err, IsNil)
+	c.Assert(ref, NotNil)
+	c.Assert(ref.Name, Equals, name)
+	c.Assert(soutput, DeepEquals, map[string]interface{}{"id": "snapshot-1", "key": redact.Mask})
+	out, err := ctrl.phaseOutput(crv1alpha1.Phase{Name: "dump", Output: soutput, OutputRef: ref})
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, output)
+
+	out, err = ctrl.phaseOutput(crv1alpha1.Phase{Name: "dump", Output: output})
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, output)
+}
+
+func (s *OutputsSuite) TestPhaseOutputNumbers(c *C) {
+	ctrl := &Controller{clientset: fake.NewSimpleClientset()}
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister"},
+	}
+	name := phaseOutputSecretName(as, 0, "0")
+	output := map[string]interface{}{"size": 12345678, "key": "s3cr3t"}
+	soutput, ref, err := ctrl.storePhaseOutput(as, name, output, []string{"key"})
+	c.Assert(err, IsNil)
+	c.Assert(ref, NotNil)
+	out, err := ctrl.phaseOutput(crv1alpha1.Phase{Name: "dump", Output: soutput, OutputRef: ref})
+	c.Assert(err, IsNil)
+	c.Assert(fmt.Sprint(out["size"]), Equals, "12345678")
+	c.Assert(out["key"], Equals, "s3cr3t")
+}
+
+func (s *OutputsSuite) TestStoreActionSetSecret(c *C) {
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister", UID: "backup-uid"},
+	}
+	user := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup-output-0-1", Namespace: "kanister"},
+		Data:       map[string][]byte{"password": []byte("user")},
+	}
+	ctrl := &Controller{clientset: fake.NewSimpleClientset(user)}
+	data := map[string][]byte{phaseOutputSecretKey: []byte(`{"key":"s3cr3t"}`)}
+
+	// A Secret that is not controlled by the ActionSet is not overwritten.
+	err := ctrl.storeActionSetSecret(as, actionSetSecretRef(as, user.Name), data)
+	c.Assert(err, NotNil)
+	us, err := ctrl.clientset.CoreV1().Secrets("kanister").Get(user.Name, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(us.Data, DeepEquals, user.Data)
+	c.Assert(us.OwnerReferences, HasLen, 0)
+
+	// The Secrets of the ActionSet are created, and updated when the action
+	// is run again.
+	ref := actionSetSecretRef(as, "backup-output-0-2")
+	err = ctrl.storeActionSetSecret(as, ref, data)
+	c.Assert(err, IsNil)
+	data = map[string][]byte{phaseOutputSecretKey: []byte(`{"key":"n3w"}`)}
+	err = ctrl.storeActionSetSecret(as, ref, data)
+	c.Assert(err, IsNil)
+	secret, err := ctrl.clientset.CoreV1().Secrets("kanister").Get(ref.Name, metav1.GetOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(secret.Data, DeepEquals, data)
+
+	// Another ActionSet with the same name does not control them.
+	other := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister", UID: "other-uid"},
+	}
+	err = ctrl.storeActionSetSecret(other, ref, data)
+	c.Assert(err, NotNil)
+}
+
+func (s *OutputsSuite) TestSensitivePhaseOutput(c *C) {
+	ctrl := &Controller{clientset: fake.NewSimpleClientset()}
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "kanister"},
+	}
+	// The encryption key is passed to a sensitive output artifact.
+	refs, err := param.SensitiveOutputs(map[string]crv1alpha1.Artifact{
+		"cloudObject": {
+			KeyValue: map[string]string{
+				"path":          "{{ .Phases.backup.Output.path }}",
+				"encryptionKey": "{{ .Phases.backup.Output.key }}",
+			},
+			Sensitive: []string{"encryptionKey"},
+		},
+	})
+	c.Assert(err, IsNil)
+	output := map[string]interface{}{"path": "/backups/1", "key": "encryption-key-value"}
+	c.Assert(sensitiveOutputKeys(refs, "backup", output), DeepEquals, []string{"key"})
+	c.Assert(sensitiveOutputKeys(refs, "other", output), IsNil)
+	c.Assert(sensitiveOutputKeys(param.PhaseOutputs{"backup": nil}, "backup", map[string]interface{}{"key": "value"}), DeepEquals, []string{"key"})
+
+	name := phaseOutputSecretName(as, 0, "0")
+	soutput, ref, err := ctrl.storePhaseOutput(as, name, output, sensitiveOutputKeys(refs, "backup", output))
+	c.Assert(err, IsNil)
+	c.Assert(ref, NotNil)
+	// The status does not contain the key.
+	c.Assert(soutput, DeepEquals, map[string]interface{}{"path": "/backups/1", "key": redact.Mask})
+	status, err := json.Marshal(crv1alpha1.Phase{Name: "backup", Output: soutput, OutputRef: ref})
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(status), "encryption-key-value"), Equals, false)
+	out, err := ctrl.phaseOutput(crv1alpha1.Phase{Name: "backup", Output: soutput, OutputRef: ref})
+	c.Assert(err, IsNil)
+	c.Assert(out, DeepEquals, output)
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/redact"
+)
+
+// actionPlan holds the rendered phases of an action of a dry-run ActionSet.
+type actionPlan struct {
+	phases     []kanister.PhasePlan
+	deferPhase *kanister.PhasePlan
+	err        error
+	// redactor redacts the secrets of the action from its plan.
+	redactor *redact.Redactor
+}
+
+// planActionSet renders the phases of the actions of a dry-run ActionSet
+// and records them in its status instead of executing them. The ActionSet
+// fails if a phase could not be rendered.
+func (c *Controller) planActionSet(as *crv1alpha1.ActionSet) error {
+	ctx := context.Background()
+	plans := make([]actionPlan, len(as.Spec.Actions))
+	for i := range as.Spec.Actions {
+		plans[i] = c.planAction(ctx, as, i)
+	}
+	var planned bool
+	var planErr string
+	if err := reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		if ras.Status == nil || (ras.Status.State != crv1alpha1.StatePending && ras.Status.State != crv1alpha1.StateQueued) {
+			return nil
+		}
+		planned = true
+		setActionSetState(ras.Status, crv1alpha1.StateRunning, "Started", "")
+		now := v1.Now()
+		var msg string
+		for i, p := range plans {
+			a := &ras.Status.Actions[i]
+			a.StartTime, a.EndTime = &now, &now
+			if m := setActionPlan(a, p); m != "" {
+				setActionError(a, m)
+				if msg == "" {
+					msg = m
+				}
+			}
+		}
+		planErr = msg
+		if msg != "" {
+			setActionSetState(ras.Status, crv1alpha1.StateFailed, "PlanFailed", msg)
+		} else {
+			setActionSetState(ras.Status, crv1alpha1.StateComplete, "Planned", "")
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if !planned {
+		return nil
+	}
+	if planErr != "" {
+		c.logAndErrorEvent(fmt.Sprintf("Failed to render the plan of ActionSet %s:", as.GetName()), "PlanFailed", errors.New(planErr), as)
+		return nil
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Rendered the plan of ActionSet %s", as.GetName()), "Planned", as)
+	return nil
+}
+
+// planAction renders the phases of an action without executing them.
+func (c *Controller) planAction(ctx context.Context, as *crv1alpha1.ActionSet, aIDX int) actionPlan {
+	bp, tp, err := c.actionParams(ctx, as, aIDX)
+	if err != nil {
+		return actionPlan{err: err}
+	}
+	phases, deferPhase, err := kanister.PlanPhases(ctx, c.clientset, *bp, as.Spec.Actions[aIDX].Name, tp)
+	return actionPlan{
+		phases:     phases,
+		deferPhase: deferPhase,
+		err:        err,
+		redactor:   redact.New(param.SecretValues(*tp)...),
+	}
+}
+
+// setActionPlan records the rendered phases in the status of the action. It
+// returns the message of the first error, if any.
+func setActionPlan(a *crv1alpha1.ActionStatus, p actionPlan) string {
+	if p.err != nil {
+		return p.redactor.String(fmt.Sprintf("Failed to render action %s: %s", a.Name, p.err))
+	}
+	var msg string
+	setPhase := func(ps *crv1alpha1.Phase, pp kanister.PhasePlan) {
+		ps.Plan = &crv1alpha1.PhasePlan{
+			Func:    pp.Func,
+			Args:    p.redactor.Map(pp.Args),
+			Objects: pp.Objects,
+			Skip:    pp.Skip,
+		}
+		switch {
+		case pp.Err != nil:
+			ps.State = crv1alpha1.StateFailed
+			ps.Error = p.redactor.String(pp.Err.Error())
+			if msg == "" {
+				msg = p.redactor.String(fmt.Sprintf("Failed to render phase %s: %s", pp.Name, pp.Err))
+			}
+		case pp.Skip:
+			ps.State = crv1alpha1.StateSkipped
+		default:
+			ps.State = crv1alpha1.StateComplete
+		}
+	}
+	for i, pp := range p.phases {
+		if i < len(a.Phases) && a.Phases[i].Name == pp.Name {
+			setPhase(&a.Phases[i], pp)
+		}
+	}
+	if p.deferPhase != nil && a.DeferPhase != nil {
+		setPhase(a.DeferPhase, *p.deferPhase)
+	}
+	return msg
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"github.com/pkg/errors"
+	. "gopkg.in/check.v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/redact"
+)
+
+type PlanSuite struct{}
+
+var _ = Suite(&PlanSuite{})
+
+func (s *PlanSuite) TestSetActionPlan(c *C) {
+	newStatus := func() *crv1alpha1.ActionStatus {
+		return &crv1alpha1.ActionStatus{
+			Name: "backup",
+			Phases: []crv1alpha1.Phase{
+				{Name: "dump", State: crv1alpha1.StatePending},
+				{Name: "quiesce", State: crv1alpha1.StatePending},
+				{Name: "upload", State: crv1alpha1.StatePending},
+			},
+			DeferPhase: &crv1alpha1.Phase{Name: "cleanup", State: crv1alpha1.StatePending},
+		}
+	}
+	p := actionPlan{
+		phases: []kanister.PhasePlan{
+			{
+				Name: "dump",
+				Func: "KubeExec",
+				Args: map[string]interface{}{
+					"command": []interface{}{"sh", "-c", "PASSWORD=s3cr3t dump"},
+				},
+				Objects: map[string]crv1alpha1.ObjectReference{
+					"creds": {Kind: "Secret", Name: "db-creds", Namespace: "db"},
+				},
+			},
+			{
+				Name: "quiesce",
+				Func: "ScaleWorkload",
+				Skip: true,
+			},
+			{
+				Name: "upload",
+				Func: "KubeTask",
+				Err:  errors.New("Failed to render key s3cr3t"),
+			},
+		},
+		deferPhase: &kanister.PhasePlan{
+			Name: "cleanup",
+			Func: "KubeExec",
+		},
+		redactor: redact.New("s3cr3t"),
+	}
+	a := newStatus()
+	msg := setActionPlan(a, p)
+	c.Assert(msg, Equals, "Failed to render phase upload: Failed to render key ***")
+
+	c.Assert(a.Phases[0].State, Equals, crv1alpha1.StateComplete)
+	c.Assert(a.Phases[0].Plan, DeepEquals, &crv1alpha1.PhasePlan{
+		Func: "KubeExec",
+		Args: map[string]interface{}{
+			"command": []interface{}{"sh", "-c", "PASSWORD=*** dump"},
+		},
+		Objects: map[string]crv1alpha1.ObjectReference{
+			"creds": {Kind: "Secret", Name: "db-creds", Namespace: "db"},
+		},
+	})
+	// The plan does not modify the rendered arguments.
+	c.Assert(p.phases[0].Args["command"], DeepEquals, []interface{}{"sh", "-c", "PASSWORD=s3cr3t dump"})
+
+	c.Assert(a.Phases[1].State, Equals, crv1alpha1.StateSkipped)
+	c.Assert(a.Phases[1].Plan.Skip, Equals, true)
+
+	c.Assert(a.Phases[2].State, Equals, crv1alpha1.StateFailed)
+	c.Assert(a.Phases[2].Error, Equals, "Failed to render key ***")
+	c.Assert(a.Phases[2].Plan.Func, Equals, "KubeTask")
+
+	c.Assert(a.DeferPhase.State, Equals, crv1alpha1.StateComplete)
+	c.Assert(a.DeferPhase.Plan.Func, Equals, "KubeExec")
+
+	// An action that could not be rendered has no plan.
+	a = newStatus()
+	msg = setActionPlan(a, actionPlan{err: errors.New("Blueprint not found")})
+	c.Assert(msg, Equals, "Failed to render action backup: Blueprint not found")
+	for _, ps := range a.Phases {
+		c.Assert(ps.State, Equals, crv1alpha1.StatePending)
+		c.Assert(ps.Plan, IsNil)
+	}
+}
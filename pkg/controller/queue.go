@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const (
+	// actionSetWorkers is the number of ActionSets that are started
+	// concurrently. Starting an ActionSet only launches its actions.
+	actionSetWorkers = 2
+	// maxActionSetRetries is how many times an ActionSet that could not be
+	// started is retried before it is dropped from the queue.
+	maxActionSetRetries = 5
+)
+
+// enqueueActionSet adds the ActionSet to the queue of ActionSets to start.
+func (c *Controller) enqueueActionSet(as *crv1alpha1.ActionSet) {
+	key, err := cache.MetaNamespaceKeyFunc(as)
+	if err != nil {
+		log.Errorf("Failed to get the key of ActionSet %s: %+v", as.GetName(), err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// runActionSetWorkers starts the ActionSets in the queue until the context is
+// cancelled.
+func (c *Controller) runActionSetWorkers(ctx context.Context) {
+	for i := 0; i < actionSetWorkers; i++ {
+		go wait.Until(func() {
+			for c.processNextActionSet() {
+			}
+		}, time.Second, ctx.Done())
+	}
+	<-ctx.Done()
+	c.queue.ShutDown()
+}
+
+// processNextActionSet starts the next ActionSet in the queue. It returns
+// false once the queue is shut down. ActionSets that could not be started
+// because of a transient error are added back to the queue, subject to its
+// rate limit.
+func (c *Controller) processNextActionSet() bool {
+	item, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(item)
+	key := item.(string)
+	err := c.syncActionSet(key)
+	switch {
+	case err == nil:
+		c.queue.Forget(item)
+	case validate.IsError(err):
+		log.Errorf("Invalid ActionSet %s: %+v", key, err)
+		c.queue.Forget(item)
+	case c.queue.NumRequeues(item) < maxActionSetRetries:
+		log.Errorf("Failed to start ActionSet %s, retrying: %+v", key, err)
+		c.queue.AddRateLimited(item)
+	default:
+		log.Errorf("Failed to start ActionSet %s: %+v", key, err)
+		c.queue.Forget(item)
+	}
+	return true
+}
+
+// syncActionSet initializes the ActionSet and starts it, or queues it until
+// the limits on the number of concurrent actions allow it to start. The
+// phases of a dry-run ActionSet are rendered instead.
+func (c *Controller) syncActionSet(key string) error {
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	as, err := c.crClient.CrV1alpha1().ActionSets(ns).Get(name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		c.removeWaitingActionSet(key)
+		return nil
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := validate.ActionSet(as); err != nil {
+		return err
+	}
+	if as.Status == nil {
+		c.initActionSetStatus(as)
+		if as, err = c.crClient.CrV1alpha1().ActionSets(ns).Get(name, metav1.GetOptions{}); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := validate.ActionSet(as); err != nil {
+			return err
+		}
+	}
+	if as.Status == nil || (as.Status.State != crv1alpha1.StatePending && as.Status.State != crv1alpha1.StateQueued) {
+		c.removeWaitingActionSet(key)
+		return nil
+	}
+	if as.Spec.Cancel {
+		c.removeWaitingActionSet(key)
+		return c.cancelActionSet(as)
+	}
+	// Dry runs do not execute actions, so they do not wait for them.
+	if as.Spec.DryRun {
+		return c.planActionSet(as)
+	}
+	if !c.admission.admit(admissionRequest{
+		key:       key,
+		namespace: ns,
+		priority:  as.Spec.Priority,
+		created:   as.GetCreationTimestamp().Time,
+		actions:   len(as.Spec.Actions),
+	}) {
+		return c.queueActionSet(as)
+	}
+	return c.handleActionSet(as)
+}
+
+// queueActionSet moves a pending ActionSet to the queued state.
+func (c *Controller) queueActionSet(as *crv1alpha1.ActionSet) error {
+	if as.Status.State == crv1alpha1.StateQueued {
+		return nil
+	}
+	var queued bool
+	if err := reconcile.ActionSet(context.TODO(), c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		queued = ras.Status.State == crv1alpha1.StatePending
+		if queued {
+			ras.Status.State = crv1alpha1.StateQueued
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if queued {
+		log.Infof("Queued ActionSet %s until fewer actions are running", as.GetName())
+	}
+	return nil
+}
+
+// removeWaitingActionSet stops waiting for the ActionSet to start, so that
+// the ActionSets queued after it may start.
+func (c *Controller) removeWaitingActionSet(key string) {
+	if c.admission.remove(key) {
+		c.requeueWaitingActionSets()
+	}
+}
+
+// releaseActions frees the slots of actions that stopped in the namespace and
+// requeues the ActionSets that wait for them.
+func (c *Controller) releaseActions(namespace string, n int) {
+	if n <= 0 {
+		return
+	}
+	c.admission.release(namespace, n)
+	c.requeueWaitingActionSets()
+}
+
+func (c *Controller) requeueWaitingActionSets() {
+	for _, key := range c.admission.waitingKeys() {
+		c.queue.Add(key)
+	}
+}
+
+// admissionRequest describes an ActionSet that waits to start.
+type admissionRequest struct {
+	key       string
+	namespace string
+	priority  int32
+	created   time.Time
+	actions   int
+}
+
+// admission limits the number of actions that run concurrently, in total and
+// in each namespace. ActionSets that do not fit wait and are started by
+// decreasing priority and then in the order in which they were created.
+type admission struct {
+	maxActions   int
+	maxNSActions int
+	mu           sync.Mutex
+	running      int
+	runningByNS  map[string]int
+	waiting      map[string]admissionRequest
+}
+
+func newAdmission(maxActions, maxNSActions int) *admission {
+	return &admission{
+		maxActions:   maxActions,
+		maxNSActions: maxNSActions,
+		runningByNS:  make(map[string]int),
+		waiting:      make(map[string]admissionRequest),
+	}
+}
+
+// fits returns true if n more actions can run with running actions and the
+// limit max. Zero means no limit. An ActionSet that has more actions than the
+// limit may start once no actions are running.
+func fits(running, n, max int) bool {
+	return max <= 0 || running == 0 || running+n <= max
+}
+
+// admit returns true and reserves slots for the actions of the ActionSet if
+// they may start now. Otherwise the ActionSet waits until admit is called
+// again. The ActionSets that are ahead of it in the queue are started first.
+// Once one of them does not fit in the total limit, those behind it wait so
+// that they do not delay it. Those that do not fit in the limit of their
+// namespace do not delay those of other namespaces.
+func (a *admission) admit(req admissionRequest) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.waiting[req.key] = req
+	running := a.running
+	runningByNS := make(map[string]int, len(a.runningByNS))
+	for ns, n := range a.runningByNS {
+		runningByNS[ns] = n
+	}
+	for _, w := range a.sortedWaiting() {
+		if !fits(running, w.actions, a.maxActions) {
+			return false
+		}
+		if !fits(runningByNS[w.namespace], w.actions, a.maxNSActions) {
+			continue
+		}
+		if w.key == req.key {
+			delete(a.waiting, req.key)
+			a.running += req.actions
+			a.runningByNS[req.namespace] += req.actions
+			return true
+		}
+		// The ActionSets ahead of this one will start once they are
+		// processed.
+		running += w.actions
+		runningByNS[w.namespace] += w.actions
+	}
+	return false
+}
+
+// sortedWaiting must be called with the admission locked.
+func (a *admission) sortedWaiting() []admissionRequest {
+	ws := make([]admissionRequest, 0, len(a.waiting))
+	for _, w := range a.waiting {
+		ws = append(ws, w)
+	}
+	sort.Slice(ws, func(i, j int) bool {
+		if ws[i].priority != ws[j].priority {
+			return ws[i].priority > ws[j].priority
+		}
+		if !ws[i].created.Equal(ws[j].created) {
+			return ws[i].created.Before(ws[j].created)
+		}
+		return ws[i].key < ws[j].key
+	})
+	return ws
+}
+
+// release frees the slots of n actions that stopped in the namespace.
+func (a *admission) release(namespace string, n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.running -= n
+	if a.runningByNS[namespace] -= n; a.runningByNS[namespace] <= 0 {
+		delete(a.runningByNS, namespace)
+	}
+}
+
+// remove stops waiting for an ActionSet. It returns false if the ActionSet
+// was not waiting.
+func (a *admission) remove(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.waiting[key]
+	delete(a.waiting, key)
+	return ok
+}
+
+// waitingKeys returns the keys of the waiting ActionSets in the order in
+// which they should start.
+func (a *admission) waitingKeys() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var keys []string
+	for _, w := range a.sortedWaiting() {
+		keys = append(keys, w.key)
+	}
+	return keys
+}
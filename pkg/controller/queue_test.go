@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type QueueSuite struct{}
+
+var _ = Suite(&QueueSuite{})
+
+func (s *QueueSuite) TestFits(c *C) {
+	for _, tc := range []struct {
+		running int
+		n       int
+		max     int
+		fits    bool
+	}{
+		{5, 5, 0, true},
+		{0, 1, 2, true},
+		{1, 1, 2, true},
+		{2, 1, 2, false},
+		{1, 2, 2, false},
+		// ActionSets with more actions than the limit run alone.
+		{0, 3, 2, true},
+	} {
+		c.Check(fits(tc.running, tc.n, tc.max), Equals, tc.fits, Commentf("%+v", tc))
+	}
+}
+
+func (s *QueueSuite) TestAdmitGlobalLimit(c *C) {
+	a := newAdmission(2, 0)
+	now := time.Now()
+	req := func(key string, priority int32, created time.Duration) admissionRequest {
+		return admissionRequest{key: "ns/" + key, namespace: "ns", priority: priority, created: now.Add(created), actions: 1}
+	}
+	c.Assert(a.admit(req("backup-1", 0, 0)), Equals, true)
+	c.Assert(a.admit(req("backup-2", 0, time.Second)), Equals, true)
+	c.Assert(a.admit(req("backup-3", 0, 2*time.Second)), Equals, false)
+	c.Assert(a.admit(req("restore", 10, 3*time.Second)), Equals, false)
+	// The restore has a higher priority than the backup queued before it.
+	c.Assert(a.waitingKeys(), DeepEquals, []string{"ns/restore", "ns/backup-3"})
+
+	a.release("ns", 1)
+	c.Assert(a.admit(req("backup-3", 0, 2*time.Second)), Equals, false)
+	c.Assert(a.admit(req("restore", 10, 3*time.Second)), Equals, true)
+	c.Assert(a.waitingKeys(), DeepEquals, []string{"ns/backup-3"})
+
+	a.release("ns", 1)
+	c.Assert(a.admit(req("backup-3", 0, 2*time.Second)), Equals, true)
+	c.Assert(a.waitingKeys(), HasLen, 0)
+
+	// Removed ActionSets do not delay the others.
+	c.Assert(a.admit(req("backup-4", 5, 0)), Equals, false)
+	c.Assert(a.remove("ns/backup-4"), Equals, true)
+	c.Assert(a.remove("ns/backup-4"), Equals, false)
+	a.release("ns", 1)
+	c.Assert(a.admit(req("backup-5", 0, 0)), Equals, true)
+}
+
+func (s *QueueSuite) TestAdmitNamespaceLimit(c *C) {
+	a := newAdmission(3, 1)
+	now := time.Now()
+	req := func(ns string, actions int, created time.Duration) admissionRequest {
+		return admissionRequest{key: ns + "/backup", namespace: ns, created: now.Add(created), actions: actions}
+	}
+	c.Assert(a.admit(req("ns1", 1, 0)), Equals, true)
+	c.Assert(a.admit(admissionRequest{key: "ns1/backup-2", namespace: "ns1", created: now.Add(time.Second), actions: 1}), Equals, false)
+	// The ActionSets of other namespaces are not delayed by those that wait
+	// for the limit of their namespace.
+	c.Assert(a.admit(req("ns2", 1, 2*time.Second)), Equals, true)
+	c.Assert(a.admit(req("ns3", 2, 3*time.Second)), Equals, false)
+	// The ActionSets behind one that does not fit in the total limit wait.
+	c.Assert(a.admit(req("ns4", 1, 4*time.Second)), Equals, false)
+
+	a.release("ns2", 1)
+	// ActionSets with more actions than the limit of their namespace start
+	// once none of its actions are running.
+	c.Assert(a.admit(req("ns3", 2, 3*time.Second)), Equals, true)
+	c.Assert(a.admit(req("ns4", 1, 4*time.Second)), Equals, false)
+
+	a.release("ns1", 1)
+	c.Assert(a.admit(req("ns4", 1, 4*time.Second)), Equals, false)
+	c.Assert(a.admit(admissionRequest{key: "ns1/backup-2", namespace: "ns1", created: now.Add(time.Second), actions: 1}), Equals, true)
+	c.Assert(a.waitingKeys(), DeepEquals, []string{"ns4/backup"})
+}
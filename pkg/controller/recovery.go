@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/kube"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+)
+
+// RecoveryPolicy defines what the controller does with the ActionSets that
+// were left running by a controller that stopped.
+type RecoveryPolicy string
+
+const (
+	// RecoveryFail fails the interrupted ActionSets. They can be resumed
+	// with the resume-from annotation.
+	RecoveryFail RecoveryPolicy = "fail"
+	// RecoveryRestart executes the interrupted ActionSets again from their
+	// first phases that did not complete.
+	RecoveryRestart RecoveryPolicy = "restart"
+)
+
+// interruptedMessage explains why a running ActionSet failed when another
+// controller took over.
+var interruptedMessage = fmt.Sprintf("The controller that was running the ActionSet stopped. Create an ActionSet with the %s annotation to resume it", crv1alpha1.ResumeFromAnnotation)
+
+// recoverInterruptedActionSets applies the recovery policy to the running
+// ActionSets in the namespace whose actions are not executed by this
+// controller. They were started by a controller that stopped, such as a
+// controller that restarted or a leader that lost its lease. The kanister
+// pods that were left by their phases are deleted. It is called before the
+// controller starts watching ActionSets, so that the ActionSets that are
+// restarted are executed once they are observed in the pending state.
+func (c *Controller) recoverInterruptedActionSets(namespace string) error {
+	asl, err := c.crClient.CrV1alpha1().ActionSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list ActionSets")
+	}
+	var interrupted []*crv1alpha1.ActionSet
+	for _, as := range asl.Items {
+		if as.Status == nil || as.Status.State != crv1alpha1.StateRunning || c.executesActionSet(as) {
+			continue
+		}
+		interrupted = append(interrupted, as)
+	}
+	if len(interrupted) == 0 {
+		return nil
+	}
+	c.deleteOrphanedPods(interrupted)
+	for _, as := range interrupted {
+		c.recoverActionSet(as)
+	}
+	return nil
+}
+
+// executesActionSet returns true if this controller executes any of the
+// ActionSet's actions.
+func (c *Controller) executesActionSet(as *crv1alpha1.ActionSet) bool {
+	if as.Spec == nil {
+		return false
+	}
+	for i := range as.Spec.Actions {
+		if _, ok := c.actionSetTombMap.Load(actionTombKey(as, i)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Controller) recoverActionSet(as *crv1alpha1.ActionSet) {
+	policy := c.opts.RecoveryPolicy
+	var recovered bool
+	if err := reconcile.ActionSet(context.TODO(), c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		if policy == RecoveryRestart {
+			recovered = restartInterrupted(ras.Status)
+		} else {
+			recovered = failInterrupted(ras.Status)
+		}
+		return nil
+	}); err != nil {
+		log.Errorf("Failed to recover interrupted ActionSet %s: %+v", as.GetName(), err)
+		return
+	}
+	switch {
+	case !recovered:
+	case policy == RecoveryRestart:
+		c.logAndSuccessEvent(fmt.Sprintf("Restarting interrupted ActionSet %s", as.GetName()), "ActionSetRestarted", as)
+	default:
+		c.logAndErrorEvent(fmt.Sprintf("ActionSet %s was interrupted:", as.GetName()), "ActionSetInterrupted", errors.New(interruptedMessage), as)
+	}
+}
+
+// failInterrupted fails a running ActionSet and its running phases. It
+// returns false if the ActionSet is not running.
+func failInterrupted(s *crv1alpha1.ActionSetStatus) bool {
+	if s == nil || s.State != crv1alpha1.StateRunning {
+		return false
+	}
+	now := metav1.Now()
+	for i := range s.Actions {
+		a := &s.Actions[i]
+		if endRunningPhases(a, crv1alpha1.StateFailed, interruptedMessage, now) {
+			setActionError(a, interruptedMessage)
+		}
+	}
+	setActionSetState(s, crv1alpha1.StateFailed, "Interrupted", interruptedMessage)
+	return true
+}
+
+// restartInterrupted moves a running ActionSet back to the pending state.
+// The phases that completed or were skipped keep their state and output, so
+// that only the other phases are executed again. It returns false if the
+// ActionSet is not running.
+func restartInterrupted(s *crv1alpha1.ActionSetStatus) bool {
+	if s == nil || s.State != crv1alpha1.StateRunning {
+		return false
+	}
+	for i := range s.Actions {
+		a := &s.Actions[i]
+		for j := range a.Phases {
+			resetPhase(&a.Phases[j])
+		}
+		if a.DeferPhase != nil {
+			resetPhase(a.DeferPhase)
+		}
+		a.EndTime = nil
+		a.Error = ""
+	}
+	s.State = crv1alpha1.StatePending
+	s.EndTime = nil
+	return true
+}
+
+// resetPhase moves a phase that did not complete or was not skipped back to
+// the pending state.
+func resetPhase(p *crv1alpha1.Phase) {
+	if p.State == crv1alpha1.StateComplete || p.State == crv1alpha1.StateSkipped {
+		return
+	}
+	*p = crv1alpha1.Phase{
+		Name:      p.Name,
+		State:     crv1alpha1.StatePending,
+		DependsOn: p.DependsOn,
+	}
+}
+
+// actionSetPodLabels returns the labels of the pods that the phases of the
+// ActionSet create, which identify the ActionSet.
+func actionSetPodLabels(as *crv1alpha1.ActionSet) map[string]string {
+	l := map[string]string{
+		crv1alpha1.ActionSetNamespaceLabel: as.GetNamespace(),
+		crv1alpha1.ActionSetUIDLabel:       string(as.GetUID()),
+	}
+	if len(validation.IsValidLabelValue(as.GetName())) == 0 {
+		l[crv1alpha1.ActionSetNameLabel] = as.GetName()
+	}
+	return l
+}
+
+// deleteOrphanedPods deletes the pods that were started by the phases of the
+// interrupted ActionSets. They are the pods labeled with the UIDs of the
+// ActionSets, in the namespaces of the ActionSets and of their objects, and
+// in the namespace of the controller, where KubeTask runs its pods by default.
+func (c *Controller) deleteOrphanedPods(interrupted []*crv1alpha1.ActionSet) {
+	// uids holds the UIDs of the interrupted ActionSets by namespace.
+	uids := make(map[string][]string)
+	add := func(ns string, uid string) {
+		if ns == "" {
+			return
+		}
+		for _, u := range uids[ns] {
+			if u == uid {
+				return
+			}
+		}
+		uids[ns] = append(uids[ns], uid)
+	}
+	cns, err := kube.GetControllerNamespace()
+	if err != nil {
+		log.Errorf("Failed to get controller namespace: %+v", err)
+	}
+	for _, as := range interrupted {
+		uid := string(as.GetUID())
+		add(as.GetNamespace(), uid)
+		add(cns, uid)
+		for _, a := range as.Status.Actions {
+			add(a.Object.Namespace, uid)
+		}
+	}
+	for ns, u := range uids {
+		sel := fmt.Sprintf("%s in (%s)", crv1alpha1.ActionSetUIDLabel, strings.Join(u, ","))
+		pods, err := c.clientset.CoreV1().Pods(ns).List(metav1.ListOptions{LabelSelector: sel})
+		if err != nil {
+			log.Errorf("Failed to list pods in namespace %s: %+v", ns, err)
+			continue
+		}
+		for _, pod := range pods.Items {
+			if err := c.clientset.CoreV1().Pods(ns).Delete(pod.GetName(), nil); err != nil {
+				log.Errorf("Failed to delete orphaned pod %s/%s: %+v", ns, pod.GetName(), err)
+				continue
+			}
+			log.Infof("Deleted orphaned pod %s/%s", ns, pod.GetName())
+		}
+	}
+}
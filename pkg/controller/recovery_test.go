@@ -0,0 +1,138 @@
+package controller
+
+import (
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type RecoverySuite struct{}
+
+var _ = Suite(&RecoverySuite{})
+
+func (s *RecoverySuite) TestFailInterrupted(c *C) {
+	start := metav1.Now()
+	st := &crv1alpha1.ActionSetStatus{
+		State:     crv1alpha1.StateRunning,
+		StartTime: &start,
+		Actions: []crv1alpha1.ActionStatus{
+			{
+				Name: "backup",
+				Phases: []crv1alpha1.Phase{
+					{Name: "dump", State: crv1alpha1.StateComplete},
+					{Name: "upload", State: crv1alpha1.StateRunning},
+					{Name: "cleanup", State: crv1alpha1.StatePending},
+				},
+				DeferPhase: &crv1alpha1.Phase{Name: "unquiesce", State: crv1alpha1.StatePending},
+			},
+			{
+				Name:    "copy",
+				Phases:  []crv1alpha1.Phase{{Name: "copy", State: crv1alpha1.StateComplete}},
+				EndTime: &start,
+			},
+		},
+	}
+	c.Assert(failInterrupted(st), Equals, true)
+	c.Assert(st.State, Equals, crv1alpha1.StateFailed)
+	c.Assert(st.EndTime, NotNil)
+	a := st.Actions[0]
+	c.Assert(a.Phases[0].State, Equals, crv1alpha1.StateComplete)
+	c.Assert(a.Phases[1].State, Equals, crv1alpha1.StateFailed)
+	c.Assert(a.Phases[1].Error, Equals, interruptedMessage)
+	c.Assert(a.Phases[1].EndTime, NotNil)
+	c.Assert(a.Phases[2].State, Equals, crv1alpha1.StatePending)
+	c.Assert(a.DeferPhase.State, Equals, crv1alpha1.StatePending)
+	c.Assert(a.Error, Equals, interruptedMessage)
+	c.Assert(a.EndTime, NotNil)
+	// Actions that had ended are not changed.
+	c.Assert(st.Actions[1].Error, Equals, "")
+	c.Assert(st.Actions[1].EndTime, Equals, &start)
+
+	// ActionSets that are not running are not changed.
+	c.Assert(failInterrupted(st), Equals, false)
+	c.Assert(failInterrupted(&crv1alpha1.ActionSetStatus{State: crv1alpha1.StatePending}), Equals, false)
+	c.Assert(failInterrupted(nil), Equals, false)
+}
+
+func (s *RecoverySuite) TestRestartInterrupted(c *C) {
+	start := metav1.Now()
+	st := &crv1alpha1.ActionSetStatus{
+		State:     crv1alpha1.StateRunning,
+		StartTime: &start,
+		Actions: []crv1alpha1.ActionStatus{
+			{
+				Name: "backup",
+				Phases: []crv1alpha1.Phase{
+					{Name: "dump", State: crv1alpha1.StateComplete, Output: map[string]interface{}{"path": "/dump"}},
+					{Name: "check", State: crv1alpha1.StateSkipped},
+					{Name: "upload", State: crv1alpha1.StateRunning, Attempts: 2, StartTime: &start, Error: "timeout"},
+					{Name: "cleanup", State: crv1alpha1.StatePending},
+				},
+				DeferPhase: &crv1alpha1.Phase{Name: "unquiesce", State: crv1alpha1.StateFailed, Error: "failed"},
+				EndTime:    &start,
+				Error:      "failed",
+			},
+		},
+	}
+	c.Assert(restartInterrupted(st), Equals, true)
+	c.Assert(st.State, Equals, crv1alpha1.StatePending)
+	c.Assert(st.StartTime, Equals, &start)
+	c.Assert(st.EndTime, IsNil)
+	a := st.Actions[0]
+	c.Assert(a.Phases, DeepEquals, []crv1alpha1.Phase{
+		{Name: "dump", State: crv1alpha1.StateComplete, Output: map[string]interface{}{"path": "/dump"}},
+		{Name: "check", State: crv1alpha1.StateSkipped},
+		{Name: "upload", State: crv1alpha1.StatePending},
+		{Name: "cleanup", State: crv1alpha1.StatePending},
+	})
+	c.Assert(*a.DeferPhase, DeepEquals, crv1alpha1.Phase{Name: "unquiesce", State: crv1alpha1.StatePending})
+	c.Assert(a.EndTime, IsNil)
+	c.Assert(a.Error, Equals, "")
+
+	c.Assert(restartInterrupted(st), Equals, false)
+	c.Assert(restartInterrupted(nil), Equals, false)
+}
+
+func (s *RecoverySuite) TestDeleteOrphanedPods(c *C) {
+	pod := func(ns, name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name, Labels: labels}}
+	}
+	interrupted := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kanister", Name: "backup", UID: "backup-uid"},
+		Status: &crv1alpha1.ActionSetStatus{
+			State:   crv1alpha1.StateRunning,
+			Actions: []crv1alpha1.ActionStatus{{Object: crv1alpha1.ObjectReference{Namespace: "mysql"}}},
+		},
+	}
+	running := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kanister", Name: "restore", UID: "restore-uid"},
+	}
+	labels := actionSetPodLabels(interrupted)
+	c.Assert(labels, DeepEquals, map[string]string{
+		crv1alpha1.ActionSetNamespaceLabel: "kanister",
+		crv1alpha1.ActionSetNameLabel:      "backup",
+		crv1alpha1.ActionSetUIDLabel:       "backup-uid",
+	})
+	cli := fake.NewSimpleClientset(
+		pod("kanister", "kanister-job-a", labels),
+		pod("mysql", "prepare-data-job-b", labels),
+		pod("kanister", "kanister-job-c", actionSetPodLabels(running)),
+		pod("mysql", "kanister-job-d", nil),
+	)
+	ctrl := &Controller{clientset: cli}
+	ctrl.deleteOrphanedPods([]*crv1alpha1.ActionSet{interrupted})
+
+	// Only the pods of the interrupted ActionSet are deleted.
+	var names []string
+	for _, ns := range []string{"kanister", "mysql"} {
+		pods, err := cli.CoreV1().Pods(ns).List(metav1.ListOptions{})
+		c.Assert(err, IsNil)
+		for _, p := range pods.Items {
+			names = append(names, p.GetName())
+		}
+	}
+	c.Assert(names, DeepEquals, []string{"kanister-job-c", "kanister-job-d"})
+}
@@ -0,0 +1,308 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+// retentionSyncPeriod is how often RetentionPolicies are applied.
+const retentionSyncPeriod = time.Minute
+
+func (c *Controller) onAddRetentionPolicy(rp *crv1alpha1.RetentionPolicy) error {
+	if err := validate.RetentionPolicy(rp); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Added invalid retention policy %s:", rp.GetName()), "InvalidRetentionPolicy", err, rp)
+		return nil
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Added retention policy %s", rp.GetName()), "Added", rp)
+	return nil
+}
+
+func (c *Controller) onUpdateRetentionPolicy(oldRP, newRP *crv1alpha1.RetentionPolicy) error {
+	if reflect.DeepEqual(oldRP.Spec, newRP.Spec) {
+		return nil
+	}
+	log.Infof("Updated RetentionPolicy '%s'", newRP.GetName())
+	if err := validate.RetentionPolicy(newRP); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Updated invalid retention policy %s:", newRP.GetName()), "InvalidRetentionPolicy", err, newRP)
+	}
+	return nil
+}
+
+func (c *Controller) onDeleteRetentionPolicy(rp *crv1alpha1.RetentionPolicy) error {
+	log.Infof("Deleted RetentionPolicy %s", rp.GetName())
+	return nil
+}
+
+// runRetentionPolicies applies the RetentionPolicies in the namespace until
+// the context is cancelled.
+func (c *Controller) runRetentionPolicies(ctx context.Context, namespace string) {
+	wait.Until(func() {
+		rpl, err := c.crClient.CrV1alpha1().RetentionPolicies(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.Errorf("Failed to list RetentionPolicies: %+v", err)
+			return
+		}
+		for _, rp := range rpl.Items {
+			if err := c.applyRetentionPolicy(rp, time.Now()); err != nil {
+				log.Errorf("Failed to apply RetentionPolicy %s: %+v", rp.GetName(), err)
+			}
+		}
+	}, retentionSyncPeriod, ctx.Done())
+}
+
+// applyRetentionPolicy creates the ActionSets that delete the artifacts of
+// the backups that the policy no longer retains at now. Once such an
+// ActionSet completes, the expired backup ActionSet is deleted, which also
+// garbage collects the delete ActionSet that it owns.
+func (c *Controller) applyRetentionPolicy(rp *crv1alpha1.RetentionPolicy, now time.Time) error {
+	status := &crv1alpha1.RetentionPolicyStatus{}
+	if err := validate.RetentionPolicy(rp); err != nil {
+		status.Error = err.Error()
+		return c.updateRetentionPolicyStatus(rp, status)
+	}
+	sel := labels.Everything()
+	if rp.Spec.Selector != nil {
+		var err error
+		if sel, err = metav1.LabelSelectorAsSelector(rp.Spec.Selector); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	ns := rp.GetNamespace()
+	asl, err := c.crClient.CrV1alpha1().ActionSets(ns).List(metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list the ActionSets of the RetentionPolicy")
+	}
+	dsel := labels.Set{crv1alpha1.RetentionPolicyLabel: rp.GetName()}.String()
+	dasl, err := c.crClient.CrV1alpha1().ActionSets(ns).List(metav1.ListOptions{LabelSelector: dsel})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list the delete ActionSets of the RetentionPolicy")
+	}
+	deletes := make(map[string]*crv1alpha1.ActionSet, len(dasl.Items))
+	for _, das := range dasl.Items {
+		deletes[das.GetAnnotations()[crv1alpha1.ExpiredActionSetAnnotation]] = das
+	}
+
+	// Backups that are being deleted no longer count towards the keep rules.
+	var backups []*crv1alpha1.ActionSet
+	for _, as := range backupActionSets(rp.Spec.Action, asl.Items) {
+		das, ok := deletes[as.GetName()]
+		if !ok {
+			backups = append(backups, as)
+			continue
+		}
+		var state crv1alpha1.State
+		if das.Status != nil {
+			state = das.Status.State
+		}
+		switch state {
+		case crv1alpha1.StateComplete:
+			err := c.crClient.CrV1alpha1().ActionSets(ns).Delete(as.GetName(), nil)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "Failed to delete expired ActionSet %s", as.GetName())
+			}
+			c.logAndSuccessEvent(fmt.Sprintf("Deleted expired ActionSet %s", as.GetName()), "BackupDeleted", rp)
+		case crv1alpha1.StateFailed, crv1alpha1.StateCancelled:
+			status.FailedDeletes = append(status.FailedDeletes, das.GetName())
+		default:
+			status.Expiring = append(status.Expiring, as.GetName())
+		}
+	}
+
+	for _, as := range expiredBackups(rp.Spec, backups, now) {
+		das, err := c.createDeleteActionSet(rp, as)
+		if err != nil {
+			return err
+		}
+		c.logAndSuccessEvent(fmt.Sprintf("Created ActionSet %s to delete expired ActionSet %s", das.GetName(), as.GetName()), "BackupExpired", rp)
+		status.Expiring = append(status.Expiring, as.GetName())
+	}
+	return c.updateRetentionPolicyStatus(rp, status)
+}
+
+// backupActionSets returns the completed ActionSets whose actions are all the
+// given backup action, most recent first. Dry runs did not create any
+// artifacts, so they are not backups.
+func backupActionSets(action string, ass []*crv1alpha1.ActionSet) []*crv1alpha1.ActionSet {
+	var backups []*crv1alpha1.ActionSet
+	for _, as := range ass {
+		if as.Spec == nil || as.Spec.DryRun || as.Status == nil || as.Status.State != crv1alpha1.StateComplete {
+			continue
+		}
+		if len(as.Spec.Actions) == 0 || len(as.Spec.Actions) != len(as.Status.Actions) {
+			continue
+		}
+		isBackup := true
+		for _, a := range as.Spec.Actions {
+			isBackup = isBackup && a.Name == action
+		}
+		if isBackup {
+			backups = append(backups, as)
+		}
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return finishTime(backups[i]).After(finishTime(backups[j]))
+	})
+	return backups
+}
+
+// expiredBackups returns the backups, which are sorted most recent first,
+// that the retention policy does not retain at now. The actions of the
+// backups are grouped by their blueprint and object and the keep rules apply
+// to each group. A backup is retained if any of its actions is.
+func expiredBackups(spec *crv1alpha1.RetentionPolicySpec, backups []*crv1alpha1.ActionSet, now time.Time) []*crv1alpha1.ActionSet {
+	groups := make(map[string][]*crv1alpha1.ActionSet)
+	var keys []string
+	for _, as := range backups {
+		for _, a := range as.Status.Actions {
+			o := a.Object
+			key := fmt.Sprintf("%s/%s/%s/%s/%s/%s", a.Blueprint, o.Group, o.Resource, o.Kind, o.Namespace, o.Name)
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], as)
+		}
+	}
+	retained := make(map[*crv1alpha1.ActionSet]bool, len(backups))
+	for _, key := range keys {
+		for _, as := range retainedBackups(spec, groups[key], now) {
+			retained[as] = true
+		}
+	}
+	var expired []*crv1alpha1.ActionSet
+	for _, as := range backups {
+		if !retained[as] {
+			expired = append(expired, as)
+		}
+	}
+	return expired
+}
+
+// retainedBackups applies the keep rules and the max age of the retention
+// policy to a group of backups sorted most recent first. Each day, week and
+// month counts once towards its rule.
+func retainedBackups(spec *crv1alpha1.RetentionPolicySpec, group []*crv1alpha1.ActionSet, now time.Time) []*crv1alpha1.ActionSet {
+	keepAll := spec.KeepLast+spec.KeepDaily+spec.KeepWeekly+spec.KeepMonthly == 0
+	rules := []*keepRule{
+		{n: spec.KeepDaily, period: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{n: spec.KeepWeekly, period: func(t time.Time) string {
+			y, w := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", y, w)
+		}},
+		{n: spec.KeepMonthly, period: func(t time.Time) string { return t.Format("2006-01") }},
+	}
+	var retained []*crv1alpha1.ActionSet
+	for i, as := range group {
+		t := finishTime(as).UTC()
+		if spec.MaxAge != nil && now.Sub(t) > spec.MaxAge.Duration {
+			continue
+		}
+		keep := keepAll || i < spec.KeepLast
+		for _, r := range rules {
+			// Every rule must see the backup so that it counts its period.
+			keep = r.keep(t) || keep
+		}
+		if keep {
+			retained = append(retained, as)
+		}
+	}
+	return retained
+}
+
+// keepRule retains the most recent backup of each of the n most recent
+// periods that have one.
+type keepRule struct {
+	n      int
+	period func(time.Time) string
+	last   string
+	kept   int
+}
+
+func (r *keepRule) keep(t time.Time) bool {
+	if r.kept >= r.n {
+		return false
+	}
+	p := r.period(t)
+	if p == r.last {
+		return false
+	}
+	r.last = p
+	r.kept++
+	return true
+}
+
+// createDeleteActionSet creates the ActionSet that deletes the artifacts of
+// the expired backup. Like an ActionSet created from a parent by kanctl, its
+// actions run the delete action on the objects of the backup with the
+// artifacts that the backup created. It is owned by the backup.
+func (c *Controller) createDeleteActionSet(rp *crv1alpha1.RetentionPolicy, parent *crv1alpha1.ActionSet) (*crv1alpha1.ActionSet, error) {
+	deleteAction := rp.Spec.DeleteAction
+	if deleteAction == "" {
+		deleteAction = crv1alpha1.DefaultDeleteAction
+	}
+	as := deleteActionSet(parent, deleteAction)
+	as.SetLabels(map[string]string{crv1alpha1.RetentionPolicyLabel: rp.GetName()})
+	cas, err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Create(as)
+	if apierrors.IsAlreadyExists(err) {
+		return c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+	}
+	return cas, errors.Wrapf(err, "Failed to create the ActionSet to delete expired ActionSet %s", parent.GetName())
+}
+
+func deleteActionSet(parent *crv1alpha1.ActionSet, deleteAction string) *crv1alpha1.ActionSet {
+	actions := make([]crv1alpha1.ActionSpec, 0, len(parent.Status.Actions))
+	for aidx, pa := range parent.Status.Actions {
+		actions = append(actions, crv1alpha1.ActionSpec{
+			Name:       deleteAction,
+			Blueprint:  pa.Blueprint,
+			Object:     pa.Object,
+			Artifacts:  pa.Artifacts,
+			Secrets:    parent.Spec.Actions[aidx].Secrets,
+			ConfigMaps: parent.Spec.Actions[aidx].ConfigMaps,
+			Profile:    parent.Spec.Actions[aidx].Profile,
+			Options:    parent.Spec.Actions[aidx].Options,
+		})
+	}
+	return &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", deleteAction, parent.GetName()),
+			Namespace: parent.GetNamespace(),
+			Annotations: map[string]string{
+				crv1alpha1.ExpiredActionSetAnnotation: parent.GetName(),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: crv1alpha1.SchemeGroupVersion.String(),
+					Kind:       crv1alpha1.ActionSetResource.Kind,
+					Name:       parent.GetName(),
+					UID:        parent.GetUID(),
+				},
+			},
+		},
+		Spec: &crv1alpha1.ActionSetSpec{
+			Actions: actions,
+		},
+	}
+}
+
+func (c *Controller) updateRetentionPolicyStatus(rp *crv1alpha1.RetentionPolicy, status *crv1alpha1.RetentionPolicyStatus) error {
+	if reflect.DeepEqual(rp.Status, status) {
+		return nil
+	}
+	rp = rp.DeepCopy()
+	rp.Status = status
+	_, err := c.crClient.CrV1alpha1().RetentionPolicies(rp.GetNamespace()).Update(rp)
+	return errors.Wrap(err, "Failed to update the status of the RetentionPolicy")
+}
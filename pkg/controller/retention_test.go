@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type RetentionSuite struct{}
+
+var _ = Suite(&RetentionSuite{})
+
+func newBackup(name string, end time.Time, objects ...string) *crv1alpha1.ActionSet {
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kanister",
+		},
+		Spec: &crv1alpha1.ActionSetSpec{},
+		Status: &crv1alpha1.ActionSetStatus{
+			State:   crv1alpha1.StateComplete,
+			EndTime: &metav1.Time{Time: end},
+		},
+	}
+	for _, o := range objects {
+		obj := crv1alpha1.ObjectReference{Kind: param.StatefulSetKind, Namespace: "mysql", Name: o}
+		as.Spec.Actions = append(as.Spec.Actions, crv1alpha1.ActionSpec{
+			Name:    "backup",
+			Object:  obj,
+			Profile: &crv1alpha1.ObjectReference{Name: "profile", Namespace: "kanister"},
+			Options: map[string]string{"key": "value"},
+		})
+		as.Status.Actions = append(as.Status.Actions, crv1alpha1.ActionStatus{
+			Name:      "backup",
+			Object:    obj,
+			Blueprint: "mysql-blueprint",
+			Artifacts: map[string]crv1alpha1.Artifact{
+				"mysqlCloudDump": {KeyValue: map[string]string{"path": name}},
+			},
+		})
+	}
+	return as
+}
+
+func backupNames(ass []*crv1alpha1.ActionSet) []string {
+	ns := []string{}
+	for _, as := range ass {
+		ns = append(ns, as.Name)
+	}
+	return ns
+}
+
+func (s *RetentionSuite) TestBackupActionSets(c *C) {
+	day := func(d int) time.Time {
+		return time.Date(2019, time.July, d, 2, 0, 0, 0, time.UTC)
+	}
+	running := newBackup("running", day(4), "mysql")
+	running.Status.State = crv1alpha1.StateRunning
+	restore := newBackup("restore", day(5), "mysql")
+	restore.Spec.Actions[0].Name = "restore"
+	dryRun := newBackup("dryRun", day(6), "mysql")
+	dryRun.Spec.DryRun = true
+	ass := []*crv1alpha1.ActionSet{
+		newBackup("day1", day(1), "mysql"),
+		newBackup("day3", day(3), "mysql"),
+		running,
+		restore,
+		dryRun,
+		newBackup("day2", day(2), "mysql", "mysql-replica"),
+	}
+	backups := backupActionSets("backup", ass)
+	c.Assert(backupNames(backups), DeepEquals, []string{"day3", "day2", "day1"})
+}
+
+func (s *RetentionSuite) TestExpiredBackups(c *C) {
+	day := func(d int) time.Time {
+		return time.Date(2019, time.July, d, 2, 0, 0, 0, time.UTC)
+	}
+	now := time.Date(2019, time.July, 31, 12, 0, 0, 0, time.UTC)
+	// Daily backups of mysql for July, most recent first.
+	var daily []*crv1alpha1.ActionSet
+	for d := 31; d > 0; d-- {
+		daily = append(daily, newBackup(fmt.Sprintf("day%d", d), day(d), "mysql"))
+	}
+	dailyExcept := func(retained ...int) []string {
+		var ns []string
+		for d := 31; d > 0; d-- {
+			keep := false
+			for _, r := range retained {
+				keep = keep || r == d
+			}
+			if !keep {
+				ns = append(ns, fmt.Sprintf("day%d", d))
+			}
+		}
+		return ns
+	}
+	for _, tc := range []struct {
+		spec    *crv1alpha1.RetentionPolicySpec
+		backups []*crv1alpha1.ActionSet
+		expired []string
+	}{
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepLast: 3},
+			backups: daily,
+			expired: dailyExcept(31, 30, 29),
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepLast: 40},
+			backups: daily,
+			expired: dailyExcept(),
+		},
+		{
+			// July 28th is the last day of the previous ISO week.
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepDaily: 2, KeepWeekly: 2},
+			backups: daily,
+			expired: dailyExcept(31, 30, 28),
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepLast: 1, KeepMonthly: 3},
+			backups: daily,
+			expired: dailyExcept(31),
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepLast: 10, MaxAge: &metav1.Duration{Duration: 48 * time.Hour}},
+			backups: daily,
+			expired: dailyExcept(31, 30),
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{MaxAge: &metav1.Duration{Duration: 72 * time.Hour}},
+			backups: daily,
+			expired: dailyExcept(31, 30, 29),
+		},
+		{
+			// Several backups on the same day count once.
+			spec: &crv1alpha1.RetentionPolicySpec{KeepDaily: 2},
+			backups: []*crv1alpha1.ActionSet{
+				newBackup("day2-night", day(2).Add(20*time.Hour), "mysql"),
+				newBackup("day2", day(2), "mysql"),
+				newBackup("day1-night", day(1).Add(20*time.Hour), "mysql"),
+				newBackup("day1", day(1), "mysql"),
+			},
+			expired: []string{"day2", "day1"},
+		},
+		{
+			// Backups are grouped by object.
+			spec: &crv1alpha1.RetentionPolicySpec{KeepLast: 1},
+			backups: []*crv1alpha1.ActionSet{
+				newBackup("mysql-day3", day(3), "mysql"),
+				newBackup("replica-day2", day(2), "mysql-replica"),
+				newBackup("mysql-day1", day(1), "mysql"),
+				newBackup("replica-day1", day(1), "mysql-replica"),
+			},
+			expired: []string{"mysql-day1", "replica-day1"},
+		},
+		{
+			// A backup is retained if any of its actions is.
+			spec: &crv1alpha1.RetentionPolicySpec{KeepLast: 1},
+			backups: []*crv1alpha1.ActionSet{
+				newBackup("day3", day(3), "mysql"),
+				newBackup("day2", day(2), "mysql", "mysql-replica"),
+				newBackup("day1", day(1), "mysql", "mysql-replica"),
+			},
+			expired: []string{"day1"},
+		},
+	} {
+		expired := expiredBackups(tc.spec, tc.backups, now)
+		c.Check(backupNames(expired), DeepEquals, append([]string{}, tc.expired...), Commentf("%#v", tc.spec))
+	}
+}
+
+func (s *RetentionSuite) TestDeleteActionSet(c *C) {
+	end := time.Date(2019, time.July, 1, 2, 0, 0, 0, time.UTC)
+	parent := newBackup("backup-abcde", end, "mysql", "mysql-replica")
+	parent.UID = "uid"
+	as := deleteActionSet(parent, "delete")
+	c.Assert(as.Name, Equals, "delete-backup-abcde")
+	c.Assert(as.Namespace, Equals, "kanister")
+	c.Assert(as.Annotations[crv1alpha1.ExpiredActionSetAnnotation], Equals, "backup-abcde")
+	c.Assert(as.OwnerReferences, HasLen, 1)
+	c.Assert(as.OwnerReferences[0].Kind, Equals, crv1alpha1.ActionSetResource.Kind)
+	c.Assert(as.OwnerReferences[0].Name, Equals, "backup-abcde")
+	c.Assert(string(as.OwnerReferences[0].UID), Equals, "uid")
+	c.Assert(as.Spec.Actions, HasLen, 2)
+	for i, a := range as.Spec.Actions {
+		c.Assert(a.Name, Equals, "delete")
+		c.Assert(a.Blueprint, Equals, "mysql-blueprint")
+		c.Assert(a.Object, DeepEquals, parent.Status.Actions[i].Object)
+		c.Assert(a.Artifacts, DeepEquals, parent.Status.Actions[i].Artifacts)
+		c.Assert(a.Profile, DeepEquals, parent.Spec.Actions[i].Profile)
+		c.Assert(a.Options, DeepEquals, parent.Spec.Actions[i].Options)
+	}
+}
@@ -0,0 +1,288 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const (
+	// scheduleSyncPeriod is how often Schedules are checked for due runs.
+	scheduleSyncPeriod = 10 * time.Second
+	// missedRunThreshold is how late a run can be started before it is
+	// considered missed.
+	missedRunThreshold = time.Minute
+)
+
+func (c *Controller) onAddSchedule(s *crv1alpha1.Schedule) error {
+	if err := validate.Schedule(s); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Added invalid schedule %s:", s.GetName()), "InvalidSchedule", err, s)
+		return nil
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Added schedule %s", s.GetName()), "Added", s)
+	return nil
+}
+
+func (c *Controller) onUpdateSchedule(oldS, newS *crv1alpha1.Schedule) error {
+	if reflect.DeepEqual(oldS.Spec, newS.Spec) {
+		return nil
+	}
+	log.Infof("Updated Schedule '%s'", newS.GetName())
+	if err := validate.Schedule(newS); err != nil {
+		c.logAndErrorEvent(fmt.Sprintf("Updated invalid schedule %s:", newS.GetName()), "InvalidSchedule", err, newS)
+	}
+	return nil
+}
+
+func (c *Controller) onDeleteSchedule(s *crv1alpha1.Schedule) error {
+	log.Infof("Deleted Schedule %s", s.GetName())
+	return nil
+}
+
+// runSchedules starts the runs of the Schedules in the namespace that are
+// due until the context is cancelled.
+func (c *Controller) runSchedules(ctx context.Context, namespace string) {
+	wait.Until(func() {
+		ss, err := c.crClient.CrV1alpha1().Schedules(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.Errorf("Failed to list Schedules: %+v", err)
+			return
+		}
+		for _, s := range ss.Items {
+			if err := c.syncSchedule(ctx, s, time.Now()); err != nil {
+				log.Errorf("Failed to run Schedule %s: %+v", s.GetName(), err)
+			}
+		}
+	}, scheduleSyncPeriod, ctx.Done())
+}
+
+// syncSchedule starts the run of the Schedule that is due at now, if any, and
+// updates its status and history.
+func (c *Controller) syncSchedule(ctx context.Context, s *crv1alpha1.Schedule, now time.Time) error {
+	status := &crv1alpha1.ScheduleStatus{}
+	if s.Status != nil {
+		status = s.Status.DeepCopy()
+	}
+	if err := validate.Schedule(s); err != nil {
+		status.Error = err.Error()
+		return c.updateScheduleStatus(s, status)
+	}
+	status.Error = ""
+	sched, err := cron.ParseStandard(s.Spec.Cron)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ns := s.GetNamespace()
+	sel := labels.Set{crv1alpha1.ScheduleLabel: s.GetName()}.String()
+	asl, err := c.crClient.CrV1alpha1().ActionSets(ns).List(metav1.ListOptions{LabelSelector: sel})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list the ActionSets of the Schedule")
+	}
+	active, finished := splitScheduleActionSets(asl.Items)
+
+	last := s.GetCreationTimestamp().Time
+	if status.LastScheduleTime != nil {
+		last = status.LastScheduleTime.Time
+	}
+	if due, start := dueRun(sched, last, now, s.Spec.MissedRunPolicy); !due.IsZero() {
+		status.LastScheduleTime = &metav1.Time{Time: due}
+		switch {
+		case !start:
+			c.logAndSuccessEvent(fmt.Sprintf("Skipped run of schedule %s missed at %s", s.GetName(), due), "RunMissed", s)
+		case s.Spec.ConcurrencyPolicy == crv1alpha1.ConcurrencyPolicyForbid && len(active) > 0:
+			c.logAndSuccessEvent(fmt.Sprintf("Skipped run of schedule %s while %s is active", s.GetName(), active[0].GetName()), "RunSkipped", s)
+		default:
+			if s.Spec.ConcurrencyPolicy == crv1alpha1.ConcurrencyPolicyReplace {
+				for _, as := range active {
+					if err := c.requestActionSetCancel(ctx, as); err != nil {
+						return err
+					}
+				}
+				active = nil
+			}
+			as, err := c.createScheduledActionSet(s, due)
+			if err != nil {
+				return err
+			}
+			active = append(active, as)
+			status.LastActionSet = as.GetName()
+		}
+	}
+
+	status.Active = nil
+	for _, as := range active {
+		status.Active = append(status.Active, as.GetName())
+	}
+	for _, as := range append(active, finished...) {
+		if as.GetName() == status.LastActionSet {
+			status.LastRunState = crv1alpha1.StatePending
+			if as.Status != nil {
+				status.LastRunState = as.Status.State
+			}
+		}
+	}
+	if err := c.pruneScheduleHistory(s, finished); err != nil {
+		return err
+	}
+	return c.updateScheduleStatus(s, status)
+}
+
+// dueRun returns the time of the most recent run of the schedule after last
+// that is due at now, or the zero time if none is. It also returns whether
+// the run should be started: a run is missed if it is more than
+// missedRunThreshold late, and missed runs are only started if the policy
+// allows it.
+func dueRun(sched cron.Schedule, last, now time.Time, policy crv1alpha1.MissedRunPolicy) (time.Time, bool) {
+	due := lastRun(sched, last.UTC(), now.UTC())
+	if due.IsZero() {
+		return due, false
+	}
+	missed := now.Sub(due) > missedRunThreshold
+	return due, !missed || policy != crv1alpha1.MissedRunPolicySkip
+}
+
+// lastRun returns the time of the most recent run of the schedule after last
+// that is not after now, or the zero time if there is none. Rather than
+// walking through every run that was missed since last, it finds a time
+// after which a run is due in windows before now of doubling length, then
+// narrows it down to the run by bisection, so that a schedule that was not
+// run for a long time is cheap to check. Runs of @every schedules are
+// computed directly.
+func lastRun(sched cron.Schedule, last, now time.Time) time.Time {
+	if d, ok := sched.(cron.ConstantDelaySchedule); ok {
+		// @every runs are a constant delay apart, starting from last.
+		first := sched.Next(last)
+		if first.After(now) {
+			return time.Time{}
+		}
+		return first.Add(now.Sub(first) / d.Delay * d.Delay)
+	}
+	// Next returns the zero time if the schedule has no further runs.
+	due := func(t time.Time) bool {
+		next := sched.Next(t)
+		return !next.IsZero() && !next.After(now)
+	}
+	lo := last
+	for w := missedRunThreshold; w > 0 && now.Add(-w).After(last); w *= 2 {
+		if due(now.Add(-w)) {
+			lo = now.Add(-w)
+			break
+		}
+	}
+	if !due(lo) {
+		return time.Time{}
+	}
+	// A run is due after lo but not after hi. Cron schedules run on whole
+	// seconds, so there is a single run between them once they are a second
+	// apart.
+	hi := now
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if due(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return sched.Next(lo)
+}
+
+// splitScheduleActionSets splits the ActionSets of a Schedule into the ones
+// that are pending or running and the ones that have finished, oldest first.
+func splitScheduleActionSets(ass []*crv1alpha1.ActionSet) (active, finished []*crv1alpha1.ActionSet) {
+	sorted := make([]*crv1alpha1.ActionSet, len(ass))
+	copy(sorted, ass)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].GetCreationTimestamp(), sorted[j].GetCreationTimestamp()
+		return ti.Before(&tj)
+	})
+	for _, as := range sorted {
+		if actionSetFinished(as) {
+			finished = append(finished, as)
+		} else {
+			active = append(active, as)
+		}
+	}
+	return active, finished
+}
+
+// createScheduledActionSet creates the ActionSet of the run of the Schedule
+// that is due at the given time. The name of the ActionSet is derived from
+// the time so that a run is started at most once, even if the status of the
+// Schedule could not be updated.
+func (c *Controller) createScheduledActionSet(s *crv1alpha1.Schedule, due time.Time) (*crv1alpha1.ActionSet, error) {
+	isController := true
+	as := &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", s.GetName(), due.Unix()),
+			Namespace: s.GetNamespace(),
+			Labels:    map[string]string{crv1alpha1.ScheduleLabel: s.GetName()},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: crv1alpha1.SchemeGroupVersion.String(),
+					Kind:       crv1alpha1.ScheduleResource.Kind,
+					Name:       s.GetName(),
+					UID:        s.GetUID(),
+					Controller: &isController,
+				},
+			},
+		},
+		Spec: s.Spec.ActionSetTemplate.DeepCopy(),
+	}
+	cas, err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Create(as)
+	if apierrors.IsAlreadyExists(err) {
+		return c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Get(as.GetName(), metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create the ActionSet of the Schedule")
+	}
+	c.logAndSuccessEvent(fmt.Sprintf("Created ActionSet %s for schedule %s", cas.GetName(), s.GetName()), "RunStarted", s)
+	return cas, nil
+}
+
+// requestActionSetCancel asks the controller to cancel the ActionSet.
+func (c *Controller) requestActionSetCancel(ctx context.Context, as *crv1alpha1.ActionSet) error {
+	return reconcile.ActionSet(ctx, c.crClient.CrV1alpha1(), as.GetNamespace(), as.GetName(), func(ras *crv1alpha1.ActionSet) error {
+		ras.Spec.Cancel = true
+		return nil
+	})
+}
+
+// pruneScheduleHistory deletes the oldest finished ActionSets of the Schedule
+// beyond its history limit.
+func (c *Controller) pruneScheduleHistory(s *crv1alpha1.Schedule, finished []*crv1alpha1.ActionSet) error {
+	if s.Spec.HistoryLimit == 0 || len(finished) <= s.Spec.HistoryLimit {
+		return nil
+	}
+	for _, as := range finished[:len(finished)-s.Spec.HistoryLimit] {
+		err := c.crClient.CrV1alpha1().ActionSets(as.GetNamespace()).Delete(as.GetName(), nil)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Failed to delete ActionSet %s", as.GetName())
+		}
+	}
+	return nil
+}
+
+func (c *Controller) updateScheduleStatus(s *crv1alpha1.Schedule, status *crv1alpha1.ScheduleStatus) error {
+	if reflect.DeepEqual(s.Status, status) {
+		return nil
+	}
+	s = s.DeepCopy()
+	s.Status = status
+	_, err := c.crClient.CrV1alpha1().Schedules(s.GetNamespace()).Update(s)
+	return errors.Wrap(err, "Failed to update the status of the Schedule")
+}
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type ScheduleSuite struct{}
+
+var _ = Suite(&ScheduleSuite{})
+
+func (s *ScheduleSuite) TestDueRun(c *C) {
+	sched, err := cron.ParseStandard("0 2 * * *")
+	c.Assert(err, IsNil)
+	day := func(d, h, m int) time.Time {
+		return time.Date(2019, time.July, d, h, m, 0, 0, time.UTC)
+	}
+	for _, tc := range []struct {
+		last   time.Time
+		now    time.Time
+		policy crv1alpha1.MissedRunPolicy
+		due    time.Time
+		start  bool
+	}{
+		{
+			// Not due yet
+			last: day(1, 2, 0),
+			now:  day(2, 1, 59),
+		},
+		{
+			last:  day(1, 2, 0),
+			now:   day(2, 2, 0),
+			due:   day(2, 2, 0),
+			start: true,
+		},
+		{
+			last:   day(1, 2, 0),
+			now:    day(2, 2, 1),
+			policy: crv1alpha1.MissedRunPolicySkip,
+			due:    day(2, 2, 0),
+			start:  true,
+		},
+		{
+			// Several missed runs start once for the most recent one.
+			last:  day(1, 2, 0),
+			now:   day(4, 12, 0),
+			due:   day(4, 2, 0),
+			start: true,
+		},
+		{
+			last:   day(1, 2, 0),
+			now:    day(4, 12, 0),
+			policy: crv1alpha1.MissedRunPolicyRunOnce,
+			due:    day(4, 2, 0),
+			start:  true,
+		},
+		{
+			last:   day(1, 2, 0),
+			now:    day(4, 12, 0),
+			policy: crv1alpha1.MissedRunPolicySkip,
+			due:    day(4, 2, 0),
+			start:  false,
+		},
+	} {
+		due, start := dueRun(sched, tc.last, tc.now, tc.policy)
+		c.Check(due.Equal(tc.due), Equals, true, Commentf("due %s, expected %s", due, tc.due))
+		c.Check(start, Equals, tc.start)
+	}
+}
+
+// countingSchedule counts the calls of Next.
+type countingSchedule struct {
+	cron.Schedule
+	calls int
+}
+
+func (s *countingSchedule) Next(t time.Time) time.Time {
+	s.calls++
+	return s.Schedule.Next(t)
+}
+
+func (s *ScheduleSuite) TestLastRun(c *C) {
+	minutely, err := cron.ParseStandard("* * * * *")
+	c.Assert(err, IsNil)
+	last := time.Date(2019, time.July, 1, 2, 0, 0, 0, time.UTC)
+	now := time.Date(2020, time.July, 1, 2, 30, 30, 0, time.UTC)
+	// A schedule that was missed for a year is not walked run by run.
+	sched := &countingSchedule{Schedule: minutely}
+	due := lastRun(sched, last, now)
+	c.Check(due.Equal(time.Date(2020, time.July, 1, 2, 30, 0, 0, time.UTC)), Equals, true, Commentf("due %s", due))
+	c.Check(sched.calls < 100, Equals, true, Commentf("%d calls", sched.calls))
+	c.Check(lastRun(minutely, last, last.Add(59*time.Second)).IsZero(), Equals, true)
+
+	every, err := cron.ParseStandard("@every 90m")
+	c.Assert(err, IsNil)
+	due = lastRun(every, last, now)
+	c.Check(due.Equal(now.Add(-30*time.Second-30*time.Minute)), Equals, true, Commentf("due %s", due))
+	c.Check(lastRun(every, last, last.Add(89*time.Minute)).IsZero(), Equals, true)
+}
+
+func (s *ScheduleSuite) TestSplitScheduleActionSets(c *C) {
+	now := time.Now()
+	newAS := func(name string, age time.Duration, status *crv1alpha1.ActionSetStatus) *crv1alpha1.ActionSet {
+		return &crv1alpha1.ActionSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: status,
+		}
+	}
+	ass := []*crv1alpha1.ActionSet{
+		newAS("complete", time.Hour, &crv1alpha1.ActionSetStatus{State: crv1alpha1.StateComplete}),
+		newAS("new", 0, nil),
+		newAS("running", 2*time.Hour, &crv1alpha1.ActionSetStatus{State: crv1alpha1.StateRunning}),
+		newAS("failed", 3*time.Hour, &crv1alpha1.ActionSetStatus{State: crv1alpha1.StateFailed}),
+		newAS("cancelled", 4*time.Hour, &crv1alpha1.ActionSetStatus{State: crv1alpha1.StateCancelled}),
+	}
+	active, finished := splitScheduleActionSets(ass)
+	names := func(ass []*crv1alpha1.ActionSet) []string {
+		var ns []string
+		for _, as := range ass {
+			ns = append(ns, as.Name)
+		}
+		return ns
+	}
+	c.Assert(names(active), DeepEquals, []string{"running", "new"})
+	c.Assert(names(finished), DeepEquals, []string{"cancelled", "failed", "complete"})
+}
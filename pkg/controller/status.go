@@ -0,0 +1,92 @@
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// setActionSetState moves the ActionSet to state and records the transition
+// in its start or end time and its conditions.
+func setActionSetState(s *crv1alpha1.ActionSetStatus, state crv1alpha1.State, reason, message string) {
+	now := metav1.Now()
+	s.State = state
+	switch state {
+	case crv1alpha1.StateRunning:
+		if s.StartTime == nil {
+			s.StartTime = &now
+		}
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionRunning, corev1.ConditionTrue, reason, message, now)
+	case crv1alpha1.StateComplete:
+		s.EndTime = &now
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionRunning, corev1.ConditionFalse, reason, message, now)
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionComplete, corev1.ConditionTrue, reason, message, now)
+	case crv1alpha1.StateFailed:
+		s.EndTime = &now
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionRunning, corev1.ConditionFalse, reason, message, now)
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionFailed, corev1.ConditionTrue, reason, message, now)
+	case crv1alpha1.StateCancelled:
+		s.EndTime = &now
+		setActionSetCondition(s, crv1alpha1.ActionSetConditionRunning, corev1.ConditionFalse, reason, message, now)
+	}
+}
+
+// setActionSetCondition adds or updates the condition of type t. The
+// transition time only changes if the status of the condition does.
+func setActionSetCondition(s *crv1alpha1.ActionSetStatus, t crv1alpha1.ActionSetConditionType, status corev1.ConditionStatus, reason, message string, now metav1.Time) {
+	for i := range s.Conditions {
+		c := &s.Conditions[i]
+		if c.Type != t {
+			continue
+		}
+		if c.Status != status {
+			c.LastTransitionTime = now
+		}
+		c.Status = status
+		c.Reason = reason
+		c.Message = message
+		return
+	}
+	s.Conditions = append(s.Conditions, crv1alpha1.ActionSetCondition{
+		Type:               t,
+		Status:             status,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setActionError records the first error that failed an action.
+func setActionError(a *crv1alpha1.ActionStatus, msg string) {
+	if a.Error == "" {
+		a.Error = msg
+	}
+}
+
+// endRunningPhases moves the running phases of the action, including its
+// deferred phase, to state with the error msg and ends the action. It returns
+// false if the action had already ended.
+func endRunningPhases(a *crv1alpha1.ActionStatus, state crv1alpha1.State, msg string, now metav1.Time) bool {
+	phases := make([]*crv1alpha1.Phase, 0, len(a.Phases)+1)
+	for i := range a.Phases {
+		phases = append(phases, &a.Phases[i])
+	}
+	if a.DeferPhase != nil {
+		phases = append(phases, a.DeferPhase)
+	}
+	for _, p := range phases {
+		if p.State == crv1alpha1.StateRunning {
+			p.State = state
+			p.EndTime = &now
+			if msg != "" {
+				p.Error = msg
+			}
+		}
+	}
+	if a.EndTime != nil {
+		return false
+	}
+	a.EndTime = &now
+	return true
+}
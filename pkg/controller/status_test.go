@@ -0,0 +1,51 @@
+package controller
+
+import (
+	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type StatusSuite struct{}
+
+var _ = Suite(&StatusSuite{})
+
+func (s *StatusSuite) TestSetActionSetState(c *C) {
+	st := &crv1alpha1.ActionSetStatus{State: crv1alpha1.StatePending}
+
+	setActionSetState(st, crv1alpha1.StateRunning, "Started", "")
+	c.Assert(st.State, Equals, crv1alpha1.StateRunning)
+	c.Assert(st.StartTime, NotNil)
+	c.Assert(st.EndTime, IsNil)
+	c.Assert(st.Conditions, HasLen, 1)
+	c.Assert(st.Conditions[0].Type, Equals, crv1alpha1.ActionSetConditionRunning)
+	c.Assert(st.Conditions[0].Status, Equals, corev1.ConditionTrue)
+	start := st.StartTime
+
+	setActionSetState(st, crv1alpha1.StateFailed, "PhaseFailed", "Phase myPhase failed")
+	c.Assert(st.State, Equals, crv1alpha1.StateFailed)
+	c.Assert(st.StartTime, Equals, start)
+	c.Assert(st.EndTime, NotNil)
+	c.Assert(st.Conditions, HasLen, 2)
+	c.Assert(st.Conditions[0].Type, Equals, crv1alpha1.ActionSetConditionRunning)
+	c.Assert(st.Conditions[0].Status, Equals, corev1.ConditionFalse)
+	c.Assert(st.Conditions[1].Type, Equals, crv1alpha1.ActionSetConditionFailed)
+	c.Assert(st.Conditions[1].Status, Equals, corev1.ConditionTrue)
+	c.Assert(st.Conditions[1].Reason, Equals, "PhaseFailed")
+	c.Assert(st.Conditions[1].Message, Equals, "Phase myPhase failed")
+
+	// The transition time only changes with the status of a condition.
+	lt := st.Conditions[1].LastTransitionTime
+	setActionSetState(st, crv1alpha1.StateFailed, "PhaseFailed", "Phase otherPhase failed")
+	c.Assert(st.Conditions, HasLen, 2)
+	c.Assert(st.Conditions[1].LastTransitionTime, Equals, lt)
+	c.Assert(st.Conditions[1].Message, Equals, "Phase otherPhase failed")
+}
+
+func (s *StatusSuite) TestSetActionError(c *C) {
+	a := &crv1alpha1.ActionStatus{}
+	setActionError(a, "first")
+	setActionError(a, "second")
+	c.Assert(a.Error, Equals, "first")
+}
@@ -5,11 +5,15 @@ import (
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/kanisterio/kanister/pkg/redact"
 )
 
+// Log logs each line of the output of a container. The values registered
+// with the redact package, such as credentials, are replaced first.
 func Log(podName string, containerName string, output string) {
 	if output != "" {
-		logs := regexp.MustCompile("[\r\n]").Split(output, -1)
+		logs := regexp.MustCompile("[\r\n]").Split(redact.String(output), -1)
 		for _, l := range logs {
 			if strings.TrimSpace(l) != "" {
 				log.Info("Pod: ", podName, " Container: ", containerName, " Out: ", l)
@@ -135,6 +135,10 @@ func (*backupDataFunc) RequiredArgs() []string {
 		BackupDataIncludePathArg, BackupDataBackupArtifactPrefixArg}
 }
 
+func (*backupDataFunc) Outputs() []string {
+	return []string{BackupDataOutputBackupID, BackupDataOutputBackupTag}
+}
+
 func getPodWriter(cli kubernetes.Interface, ctx context.Context, namespace, podName, containerName string, profile *param.Profile) (*kube.PodWriter, error) {
 	if profile.Location.Type == crv1alpha1.LocationTypeGCS {
 		pw := kube.NewPodWriter(cli, restic.GoogleCloudCredsFilePath, bytes.NewBufferString(profile.Credential.KeyPair.Secret))
@@ -56,6 +56,7 @@ func copyVolumeData(ctx context.Context, cli kubernetes.Interface, tp param.Temp
 		Image:        kanisterToolsImage,
 		Command:      []string{"sh", "-c", "tail -f /dev/null"},
 		Volumes:      map[string]string{pvc: mountPoint},
+		Labels:       tp.PodLabels,
 	}
 	pr := kube.NewPodRunner(cli, options)
 	podFunc := copyVolumeDataPodFunc(cli, tp, namespace, mountPoint, targetPath, encryptionKey)
@@ -126,3 +127,12 @@ func (*copyVolumeDataFunc) Exec(ctx context.Context, tp param.TemplateParams, ar
 func (*copyVolumeDataFunc) RequiredArgs() []string {
 	return []string{CopyVolumeDataNamespaceArg, CopyVolumeDataVolumeArg, CopyVolumeDataArtifactPrefixArg}
 }
+
+func (*copyVolumeDataFunc) Outputs() []string {
+	return []string{
+		CopyVolumeDataOutputBackupID,
+		CopyVolumeDataOutputBackupRoot,
+		CopyVolumeDataOutputBackupArtifactLocation,
+		CopyVolumeDataOutputBackupTag,
+	}
+}
@@ -35,6 +35,7 @@ const (
 	CreateVolumeSnapshotNamespaceArg = "namespace"
 	CreateVolumeSnapshotPVCsArg      = "pvcs"
 	CreateVolumeSnapshotSkipWaitArg  = "skipWait"
+	CreateVolumeSnapshotOutputInfo   = "volumeSnapshotInfo"
 )
 
 type createVolumeSnapshotFunc struct{}
@@ -131,7 +132,7 @@ func createVolumeSnapshot(ctx context.Context, tp param.TemplateParams, cli kube
 		return nil, errors.Wrapf(err, "Failed to encode JSON data")
 	}
 
-	return map[string]interface{}{"volumeSnapshotInfo": string(manifestData)}, nil
+	return map[string]interface{}{CreateVolumeSnapshotOutputInfo: string(manifestData)}, nil
 }
 
 func snapshotVolume(ctx context.Context, volume volumeInfo, namespace string, skipWait bool) (*VolumeSnapshotInfo, error) {
@@ -286,3 +287,7 @@ func (kef *createVolumeSnapshotFunc) Exec(ctx context.Context, tp param.Template
 func (*createVolumeSnapshotFunc) RequiredArgs() []string {
 	return []string{CreateVolumeSnapshotNamespaceArg}
 }
+
+func (*createVolumeSnapshotFunc) Outputs() []string {
+	return []string{CreateVolumeSnapshotOutputInfo}
+}
@@ -48,6 +48,7 @@ func deleteData(ctx context.Context, cli kubernetes.Interface, tp param.Template
 		GenerateName: deleteDataJobPrefix,
 		Image:        kanisterToolsImage,
 		Command:      []string{"sh", "-c", "tail -f /dev/null"},
+		Labels:       tp.PodLabels,
 	}
 	pr := kube.NewPodRunner(cli, options)
 	podFunc := deleteDataPodFunc(cli, tp, reclaimSpace, namespace, targetPath, deleteTag, deleteIdentifier, encryptionKey)
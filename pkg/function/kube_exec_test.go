@@ -162,6 +162,7 @@ func (s *KubeExecTest) TestParseLogAndCreateOutput(c *C) {
 			map[string]interface{}{"version": "0.20.0", "path": "/backup/path"}, IsNil, NotNil},
 		{"Random message ###Phase-output###: {\"key\":\"version\",\"value\":\"0.20.0\"}", map[string]interface{}{"version": "0.20.0"}, IsNil, NotNil},
 		{"Random message with newline \n###Phase-output###: {\"key\":\"version\",\"value\":\"0.20.0\"}", map[string]interface{}{"version": "0.20.0"}, IsNil, NotNil},
+		{"###Phase-output###: {\"key\":\"ids\",\"value\":[\"a1\",\"b2\"]}", map[string]interface{}{"ids": []interface{}{"a1", "b2"}}, IsNil, NotNil},
 		{"###Phase-output###: Invalid message", nil, NotNil, IsNil},
 		{"Random message", nil, IsNil, IsNil},
 	} {
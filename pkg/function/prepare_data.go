@@ -59,7 +59,7 @@ func getVolumes(tp param.TemplateParams) (map[string]string, error) {
 	return vols, nil
 }
 
-func prepareData(ctx context.Context, cli kubernetes.Interface, namespace, serviceAccount, image string, vols map[string]string, command ...string) (map[string]interface{}, error) {
+func prepareData(ctx context.Context, cli kubernetes.Interface, tp param.TemplateParams, namespace, serviceAccount, image string, vols map[string]string, command ...string) (map[string]interface{}, error) {
 	// Validate volumes
 	for pvc := range vols {
 		if _, err := cli.CoreV1().PersistentVolumeClaims(namespace).Get(pvc, metav1.GetOptions{}); err != nil {
@@ -73,6 +73,7 @@ func prepareData(ctx context.Context, cli kubernetes.Interface, namespace, servi
 		Command:            command,
 		Volumes:            vols,
 		ServiceAccountName: serviceAccount,
+		Labels:             tp.PodLabels,
 	}
 	pr := kube.NewPodRunner(cli, options)
 	podFunc := prepareDataPodFunc(cli)
@@ -125,7 +126,7 @@ func (*prepareDataFunc) Exec(ctx context.Context, tp param.TemplateParams, args
 			return nil, err
 		}
 	}
-	return prepareData(ctx, cli, namespace, serviceAccount, image, vols, command...)
+	return prepareData(ctx, cli, tp, namespace, serviceAccount, image, vols, command...)
 }
 
 func (*prepareDataFunc) RequiredArgs() []string {
@@ -113,6 +113,7 @@ func restoreData(ctx context.Context, cli kubernetes.Interface, tp param.Templat
 		Image:        kanisterToolsImage,
 		Command:      []string{"sh", "-c", "tail -f /dev/null"},
 		Volumes:      vols,
+		Labels:       tp.PodLabels,
 	}
 	pr := kube.NewPodRunner(cli, options)
 	podFunc := restoreDataPodFunc(cli, tp, namespace, encryptionKey, backupArtifactPrefix, restorePath, backupTag, backupID)
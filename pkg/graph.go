@@ -0,0 +1,188 @@
+package kanister
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// ValidatePhaseDependencies returns an error if the phases of the action
+// depend on unknown phases or if their dependencies form a cycle.
+func ValidatePhaseDependencies(a crv1alpha1.BlueprintAction) error {
+	_, err := phaseDependencies(a.Phases)
+	return err
+}
+
+// PrecedingPhases returns, for each phase of the action, the indices of the
+// phases that are guaranteed to have completed before it is executed.
+func PrecedingPhases(a crv1alpha1.BlueprintAction) ([][]int, error) {
+	deps, err := phaseDependencies(a.Phases)
+	if err != nil {
+		return nil, err
+	}
+	preceding := make([][]int, len(deps))
+	var visit func(i int) []int
+	visit = func(i int) []int {
+		if preceding[i] != nil {
+			return preceding[i]
+		}
+		seen := make(map[int]bool)
+		for _, d := range deps[i] {
+			seen[d] = true
+			for _, j := range visit(d) {
+				seen[j] = true
+			}
+		}
+		p := make([]int, 0, len(seen))
+		for j := range seen {
+			p = append(p, j)
+		}
+		sort.Ints(p)
+		preceding[i] = p
+		return p
+	}
+	for i := range deps {
+		visit(i)
+	}
+	return preceding, nil
+}
+
+// phaseDependencies returns the indices of the phases that each phase
+// depends on. If none of the phases declare dependencies, every phase depends
+// on the one before it so that they are executed in order.
+func phaseDependencies(phases []crv1alpha1.BlueprintPhase) ([][]int, error) {
+	deps := make([][]int, len(phases))
+	if !hasDependencies(phases) {
+		for i := 1; i < len(phases); i++ {
+			deps[i] = []int{i - 1}
+		}
+		return deps, nil
+	}
+	idx := make(map[string]int, len(phases))
+	for i, p := range phases {
+		if _, ok := idx[p.Name]; ok {
+			return nil, errors.Errorf("Phase name %s is not unique", p.Name)
+		}
+		idx[p.Name] = i
+	}
+	for i, p := range phases {
+		for _, d := range p.DependsOn {
+			j, ok := idx[d]
+			if !ok {
+				return nil, errors.Errorf("Phase %s depends on unknown phase %s", p.Name, d)
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+	if err := checkCycles(phases, deps); err != nil {
+		return nil, err
+	}
+	return deps, nil
+}
+
+func hasDependencies(phases []crv1alpha1.BlueprintPhase) bool {
+	for _, p := range phases {
+		if len(p.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCycles removes phases without unresolved dependencies until none are
+// left. Any phases that remain are part of, or depend on, a cycle.
+func checkCycles(phases []crv1alpha1.BlueprintPhase, deps [][]int) error {
+	pending := make([]int, len(phases))
+	dependents := make([][]int, len(phases))
+	ready := make([]int, 0, len(phases))
+	for i, d := range deps {
+		pending[i] = len(d)
+		for _, j := range d {
+			dependents[j] = append(dependents[j], i)
+		}
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		for _, j := range dependents[i] {
+			pending[j]--
+			if pending[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	var cycle []string
+	for i, n := range pending {
+		if n > 0 {
+			cycle = append(cycle, phases[i].Name)
+		}
+	}
+	if len(cycle) == 0 {
+		return nil
+	}
+	sort.Strings(cycle)
+	return errors.Errorf("Phase dependencies form a cycle: %s", strings.Join(cycle, ", "))
+}
+
+// ExecPhases calls run for each phase once all the phases it depends on have
+// succeeded. At most parallelism phases are run concurrently. A parallelism
+// of zero or less means there is no limit. Once run returns false for a
+// phase, no further phases are started. ExecPhases waits for the phases that
+// are already running and returns true iff every phase succeeded.
+func ExecPhases(phases []*Phase, parallelism int, run func(i int) bool) bool {
+	if parallelism <= 0 || parallelism > len(phases) {
+		parallelism = len(phases)
+	}
+	pending := make([]int, len(phases))
+	dependents := make([][]int, len(phases))
+	ready := make([]int, 0, len(phases))
+	for i, p := range phases {
+		pending[i] = len(p.deps)
+		for _, j := range p.deps {
+			dependents[j] = append(dependents[j], i)
+		}
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	type result struct {
+		i  int
+		ok bool
+	}
+	results := make(chan result)
+	running, done := 0, 0
+	failed := false
+	for done < len(phases) {
+		for !failed && running < parallelism && len(ready) > 0 {
+			i := ready[0]
+			ready = ready[1:]
+			running++
+			go func() {
+				results <- result{i: i, ok: run(i)}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+		r := <-results
+		running--
+		done++
+		if !r.ok {
+			failed = true
+			continue
+		}
+		for _, j := range dependents[r.i] {
+			pending[j]--
+			if pending[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+	return !failed && done == len(phases)
+}
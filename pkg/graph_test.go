@@ -0,0 +1,193 @@
+package kanister
+
+import (
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type GraphSuite struct{}
+
+var _ = Suite(&GraphSuite{})
+
+func (s *GraphSuite) TestPhaseDependencies(c *C) {
+	for _, tc := range []struct {
+		phases  []crv1alpha1.BlueprintPhase
+		deps    [][]int
+		checker Checker
+	}{
+		{
+			phases:  nil,
+			deps:    [][]int{},
+			checker: IsNil,
+		},
+		{
+			// Without dependsOn, phases are sequential.
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c"},
+			},
+			deps:    [][]int{nil, {0}, {1}},
+			checker: IsNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c", DependsOn: []string{"a", "b"}},
+			},
+			deps:    [][]int{nil, nil, {0, 1}},
+			checker: IsNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"d"}},
+			},
+			checker: NotNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			checker: NotNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			checker: NotNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b", DependsOn: []string{"a", "d"}},
+				{Name: "c", DependsOn: []string{"b"}},
+				{Name: "d", DependsOn: []string{"c"}},
+			},
+			checker: NotNil,
+		},
+	} {
+		deps, err := phaseDependencies(tc.phases)
+		c.Check(err, tc.checker)
+		if err == nil {
+			c.Check(deps, DeepEquals, tc.deps)
+		}
+	}
+}
+
+func (s *GraphSuite) TestPrecedingPhases(c *C) {
+	for _, tc := range []struct {
+		phases    []crv1alpha1.BlueprintPhase
+		preceding [][]int
+		checker   Checker
+	}{
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c"},
+			},
+			preceding: [][]int{{}, {0}, {0, 1}},
+			checker:   IsNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a"},
+				{Name: "b"},
+				{Name: "c", DependsOn: []string{"a"}},
+				{Name: "d", DependsOn: []string{"c", "b"}},
+			},
+			preceding: [][]int{{}, {}, {0}, {0, 1, 2}},
+			checker:   IsNil,
+		},
+		{
+			phases: []crv1alpha1.BlueprintPhase{
+				{Name: "a", DependsOn: []string{"a"}},
+			},
+			checker: NotNil,
+		},
+	} {
+		preceding, err := PrecedingPhases(crv1alpha1.BlueprintAction{Phases: tc.phases})
+		c.Check(err, tc.checker)
+		if err == nil {
+			c.Check(preceding, DeepEquals, tc.preceding)
+		}
+	}
+}
+
+func (s *GraphSuite) TestExecPhases(c *C) {
+	for _, tc := range []struct {
+		deps        [][]int
+		parallelism int
+		fail        int
+		ran         []bool
+		ok          bool
+	}{
+		{
+			deps:        [][]int{nil, {0}, {1}},
+			parallelism: 0,
+			fail:        -1,
+			ran:         []bool{true, true, true},
+			ok:          true,
+		},
+		{
+			deps:        [][]int{nil, nil, nil, {0, 1, 2}},
+			parallelism: 2,
+			fail:        -1,
+			ran:         []bool{true, true, true, true},
+			ok:          true,
+		},
+		{
+			deps:        [][]int{nil, {0}, {1}},
+			parallelism: 0,
+			fail:        1,
+			ran:         []bool{true, true, false},
+			ok:          false,
+		},
+		{
+			// Phases that do not depend on the failed one still complete
+			// if they were already running.
+			deps:        [][]int{nil, nil, {0}},
+			parallelism: 0,
+			fail:        0,
+			ran:         []bool{true, true, false},
+			ok:          false,
+		},
+	} {
+		phases := make([]*Phase, len(tc.deps))
+		for i := range phases {
+			phases[i] = &Phase{deps: tc.deps[i]}
+		}
+		var mu sync.Mutex
+		ran := make([]bool, len(phases))
+		running, maxRunning := 0, 0
+		ok := ExecPhases(phases, tc.parallelism, func(i int) bool {
+			mu.Lock()
+			for _, d := range tc.deps[i] {
+				c.Check(ran[d], Equals, true)
+			}
+			ran[i] = true
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return i != tc.fail
+		})
+		c.Check(ok, Equals, tc.ok)
+		c.Check(ran, DeepEquals, tc.ran)
+		if tc.parallelism > 0 {
+			c.Check(maxRunning <= tc.parallelism, Equals, true)
+		}
+	}
+}
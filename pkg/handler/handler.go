@@ -5,12 +5,15 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/kanisterio/kanister/pkg/metrics"
 	"github.com/kanisterio/kanister/pkg/version"
 )
 
 const (
 	healthCheckPath = "/v0/healthz"
 	healthCheckAddr = ":8000"
+	metricsPath     = "/metrics"
+	readinessPath   = "/v0/readyz"
 )
 
 // Info provides information about kanister controller
@@ -41,9 +44,54 @@ func (*healthCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, string(js))
 }
 
-// NewServer returns a pointer to the http Server
-func NewServer() *http.Server {
+// Leadership reports whether this replica of the controller is the leader
+// that processes the custom resources.
+type Leadership interface {
+	IsLeader() bool
+	Leader() string
+}
+
+// Readiness provides information about the leadership of kanister controller
+type Readiness struct {
+	Ready  bool   `json:"ready"`
+	Leader string `json:"leader,omitempty"`
+}
+
+var _ http.Handler = (*readinessHandler)(nil)
+
+// readinessHandler reports the controller as ready while it is the leader.
+// Standby replicas are alive but not ready.
+type readinessHandler struct {
+	leadership Leadership
+}
+
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info := Readiness{Ready: true}
+	if h.leadership != nil {
+		info = Readiness{Ready: h.leadership.IsLeader(), Leader: h.leadership.Leader()}
+	}
+	js, err := json.Marshal(info)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if info.Ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	io.WriteString(w, string(js))
+}
+
+// NewServer returns a pointer to the http Server, which serves the health
+// check, the readiness and the Prometheus metrics of the controller. If
+// leadership is nil, leader election is disabled and the controller is
+// always ready.
+func NewServer(leadership Leadership) *http.Server {
 	m := &http.ServeMux{}
 	m.Handle(healthCheckPath, &healthCheckHandler{})
+	m.Handle(readinessPath, &readinessHandler{leadership: leadership})
+	m.Handle(metricsPath, metrics.Handler())
 	return &http.Server{Addr: healthCheckAddr, Handler: m}
 }
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type HandlerSuite struct{}
+
+var _ = Suite(&HandlerSuite{})
+
+type fakeLeadership struct {
+	leading bool
+	leader  string
+}
+
+func (l fakeLeadership) IsLeader() bool { return l.leading }
+func (l fakeLeadership) Leader() string { return l.leader }
+
+func (s *HandlerSuite) TestReadiness(c *C) {
+	for _, tc := range []struct {
+		leadership Leadership
+		code       int
+		info       Readiness
+	}{
+		{nil, http.StatusOK, Readiness{Ready: true}},
+		{fakeLeadership{true, "pod-1"}, http.StatusOK, Readiness{Ready: true, Leader: "pod-1"}},
+		{fakeLeadership{false, "pod-1"}, http.StatusServiceUnavailable, Readiness{Ready: false, Leader: "pod-1"}},
+	} {
+		srv := NewServer(tc.leadership)
+		w := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, readinessPath, nil))
+		c.Check(w.Code, Equals, tc.code)
+		var info Readiness
+		c.Assert(json.Unmarshal(w.Body.Bytes(), &info), IsNil)
+		c.Check(info, DeepEquals, tc.info)
+	}
+}
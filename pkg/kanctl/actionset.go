@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -15,10 +16,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/binding"
 	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
 	"github.com/kanisterio/kanister/pkg/kube"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/poll"
 )
 
 const (
@@ -37,19 +41,29 @@ const (
 	selectorNamespaceFlag    = "selector-namespace"
 	namespaceTargetsFlagName = "namespacetargets"
 	objectsFlagName          = "objects"
+	resumeFlagName           = "resume"
+	priorityFlagName         = "priority"
+	planFlagName             = "plan"
 )
 
+// planTimeout is how long kanctl waits for the controller to render the plan
+// of an action set.
+const planTimeout = 5 * time.Minute
+
 type performParams struct {
 	namespace  string
 	actionName string
 	parentName string
 	blueprint  string
 	dryRun     bool
+	plan       bool
+	resume     bool
 	objects    []crv1alpha1.ObjectReference
 	options    map[string]string
 	profile    *crv1alpha1.ObjectReference
 	secrets    map[string]crv1alpha1.ObjectReference
 	configMaps map[string]crv1alpha1.ObjectReference
+	priority   *int32
 }
 
 func newActionSetCmd() *cobra.Command {
@@ -64,7 +78,7 @@ func newActionSetCmd() *cobra.Command {
 	cmd.Flags().StringP(sourceFlagName, "f", "", "specify name of the action set")
 
 	cmd.Flags().StringP(actionFlagName, "a", "", "action for the action set (required if creating a new action set)")
-	cmd.Flags().StringP(blueprintFlagName, "b", "", "blueprint for the action set (required if creating a new action set)")
+	cmd.Flags().StringP(blueprintFlagName, "b", "", "blueprint for the action set, optionally as namespace/name (if not set, the blueprint is selected by the BlueprintBindings of the objects)")
 	cmd.Flags().StringSliceP(configMapsFlagName, "c", []string{}, "config maps for the action set, comma separated ref=namespace/name pairs (eg: --config-maps ref1=namespace1/name1,ref2=namespace2/name2)")
 	cmd.Flags().StringSliceP(deploymentFlagName, "d", []string{}, "deployment for the action set, comma separated namespace/name pairs (eg: --deployment namespace1/name1,namespace2/name2)")
 	cmd.Flags().StringSliceP(optionsFlagName, "o", []string{}, "specify options for the action set, comma separated key=value pairs (eg: --options key1=value1,key2=value2)")
@@ -77,6 +91,9 @@ func newActionSetCmd() *cobra.Command {
 	cmd.Flags().String(selectorNamespaceFlag, "", "namespace to apply selector on. Used along with the selector specified using --selector/-l")
 	cmd.Flags().StringSliceP(namespaceTargetsFlagName, "T", []string{}, "namespaces for the action set, comma separated list of namespaces (eg: --namespacetargets namespace1,namespace2)")
 	cmd.Flags().StringSliceP(objectsFlagName, "O", []string{}, "objects for the action set, comma separated list of object references (eg: --objects group/version/resource/namespace1/name1,group/version/resource/namespace2/name2)")
+	cmd.Flags().Bool(resumeFlagName, false, "resume the failed or cancelled action set specified using --from, skipping its completed phases")
+	cmd.Flags().Int32(priorityFlagName, 0, "priority of the action set when it is queued, action sets with a higher priority start first")
+	cmd.Flags().Bool(planFlagName, false, "if set, the action set is created as a dry run and the phases rendered by the controller are printed instead of being executed")
 	return cmd
 }
 
@@ -98,20 +115,25 @@ func initializeAndPerform(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
-	return perform(ctx, crCli, params)
+	return perform(ctx, cli, crCli, params, !valFlag)
 }
 
-func perform(ctx context.Context, crCli versioned.Interface, params *performParams) error {
+func perform(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, params *performParams, verify bool) error {
 	var as *crv1alpha1.ActionSet
 	var err error
 
 	switch {
 	case params.parentName != "":
-		pas, err := crCli.CrV1alpha1().ActionSets(params.namespace).Get(params.parentName, metav1.GetOptions{})
+		var pas *crv1alpha1.ActionSet
+		pas, err = crCli.CrV1alpha1().ActionSets(params.namespace).Get(params.parentName, metav1.GetOptions{})
 		if err != nil {
 			return err
 		}
-		as, err = childActionSet(pas, params)
+		if params.resume {
+			as, err = resumeActionSet(pas)
+		} else {
+			as, err = childActionSet(pas, params)
+		}
 	case len(params.objects) > 0:
 		as, err = newActionSet(params)
 	default:
@@ -120,6 +142,18 @@ func perform(ctx context.Context, crCli versioned.Interface, params *performPara
 	if err != nil {
 		return err
 	}
+	if params.priority != nil {
+		as.Spec.Priority = *params.priority
+	}
+	if verify {
+		if err := verifyOptions(ctx, cli, crCli, params.namespace, as); err != nil {
+			return err
+		}
+	}
+	if params.plan {
+		as.Spec.DryRun = true
+		return planActionSet(ctx, crCli, params.namespace, as)
+	}
 	if params.dryRun {
 		return printActionSet(as)
 	}
@@ -130,9 +164,6 @@ func newActionSet(params *performParams) (*crv1alpha1.ActionSet, error) {
 	if params.actionName == "" {
 		return nil, errors.New("action required to create new action set")
 	}
-	if params.blueprint == "" {
-		return nil, errors.New("blueprint required to create new action set")
-	}
 	actions := make([]crv1alpha1.ActionSpec, 0, len(params.objects))
 	for _, obj := range params.objects {
 		actions = append(actions, crv1alpha1.ActionSpec{
@@ -160,6 +191,9 @@ func childActionSet(parent *crv1alpha1.ActionSet, params *performParams) (*crv1a
 	if parent.Status == nil || parent.Status.State != crv1alpha1.StateComplete {
 		return nil, errors.Errorf("Request parent ActionSet %s has not been executed", parent.GetName())
 	}
+	if parent.Spec.DryRun {
+		return nil, errors.Errorf("Request parent ActionSet %s is a dry run", parent.GetName())
+	}
 
 	actions := make([]crv1alpha1.ActionSpec, 0, len(parent.Status.Actions)*max(1, len(params.objects)))
 	for aidx, pa := range parent.Status.Actions {
@@ -215,6 +249,29 @@ func childActionSet(parent *crv1alpha1.ActionSet, params *performParams) (*crv1a
 	}, nil
 }
 
+// resumeActionSet returns a new ActionSet with the same spec as the failed or
+// cancelled parent. The controller skips the phases that completed in the
+// parent and reuses their output.
+func resumeActionSet(parent *crv1alpha1.ActionSet) (*crv1alpha1.ActionSet, error) {
+	if parent.Status == nil || (parent.Status.State != crv1alpha1.StateFailed && parent.Status.State != crv1alpha1.StateCancelled) {
+		return nil, errors.Errorf("Request parent ActionSet %s has not failed or been cancelled", parent.GetName())
+	}
+	if parent.Spec.DryRun {
+		return nil, errors.Errorf("Request parent ActionSet %s is a dry run", parent.GetName())
+	}
+	spec := parent.Spec.DeepCopy()
+	spec.Cancel = false
+	return &crv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", parent.GetName()),
+			Annotations: map[string]string{
+				crv1alpha1.ResumeFromAnnotation: parent.GetName(),
+			},
+		},
+		Spec: spec,
+	}, nil
+}
+
 func createActionSet(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
 	as, err := crCli.CrV1alpha1().ActionSets(namespace).Create(as)
 	if err == nil {
@@ -223,6 +280,36 @@ func createActionSet(ctx context.Context, crCli versioned.Interface, namespace s
 	return err
 }
 
+// planActionSet creates a dry-run action set, waits for the controller to
+// render its phases and prints it with its status.
+func planActionSet(ctx context.Context, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
+	as, err := crCli.CrV1alpha1().ActionSets(namespace).Create(as)
+	if err != nil {
+		return err
+	}
+	name := as.GetName()
+	ctx, cancel := context.WithTimeout(ctx, planTimeout)
+	defer cancel()
+	err = poll.Wait(ctx, func(ctx context.Context) (bool, error) {
+		pas, err := crCli.CrV1alpha1().ActionSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		as = pas
+		return as.Status != nil && (as.Status.State == crv1alpha1.StateComplete || as.Status.State == crv1alpha1.StateFailed), nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to wait for the plan of action set %s", name)
+	}
+	if err = printActionSet(as); err != nil {
+		return err
+	}
+	if as.Status.State == crv1alpha1.StateFailed {
+		return errors.Errorf("Failed to render the plan of action set %s", name)
+	}
+	return nil
+}
+
 func printActionSet(as *crv1alpha1.ActionSet) error {
 	as.TypeMeta = metav1.TypeMeta{
 		Kind:       crv1alpha1.ActionSetResource.Kind,
@@ -248,6 +335,11 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 	parentName, _ := cmd.Flags().GetString(sourceFlagName)
 	blueprint, _ := cmd.Flags().GetString(blueprintFlagName)
 	dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+	plan, _ := cmd.Flags().GetBool(planFlagName)
+	resume, _ := cmd.Flags().GetBool(resumeFlagName)
+	if resume && (parentName == "" || actionName != "" || blueprint != "") {
+		return nil, errors.Errorf("--%s requires --%s and cannot be used with --%s or --%s", resumeFlagName, sourceFlagName, actionFlagName, blueprintFlagName)
+	}
 	profile, err := parseProfile(cmd, ns)
 	if err != nil {
 		return nil, err
@@ -268,17 +360,25 @@ func extractPerformParams(cmd *cobra.Command, args []string, cli kubernetes.Inte
 	if err != nil {
 		return nil, err
 	}
+	var priority *int32
+	if cmd.Flags().Changed(priorityFlagName) {
+		p, _ := cmd.Flags().GetInt32(priorityFlagName)
+		priority = &p
+	}
 	return &performParams{
 		namespace:  ns,
 		actionName: actionName,
 		parentName: parentName,
 		blueprint:  blueprint,
 		dryRun:     dryRun,
+		plan:       plan,
+		resume:     resume,
 		objects:    objects,
 		options:    options,
 		secrets:    secrets,
 		configMaps: cms,
 		profile:    profile,
+		priority:   priority,
 	}, nil
 }
 
@@ -563,9 +663,21 @@ func verifyParams(ctx context.Context, p *performParams, cli kubernetes.Interfac
 	go func() {
 		defer wg.Done()
 		if p.blueprint != "" {
-			_, err := crCli.CrV1alpha1().Blueprints(p.namespace).Get(p.blueprint, metav1.GetOptions{})
+			ns, name := blueprintRef(p.namespace, p.blueprint)
+			_, err := crCli.CrV1alpha1().Blueprints(ns).Get(name, metav1.GetOptions{})
 			if err != nil {
-				msgs <- errors.Wrapf(err, notFoundTmpl, "blueprint", p.blueprint, p.namespace)
+				msgs <- errors.Wrapf(err, notFoundTmpl, "blueprint", name, ns)
+			}
+			return
+		}
+		if p.parentName != "" {
+			return
+		}
+		// The controller selects the blueprint of each object from the
+		// BlueprintBindings.
+		for _, obj := range p.objects {
+			if _, err := binding.Resolve(ctx, cli, crCli, p.namespace, obj); err != nil {
+				msgs <- errors.Wrapf(err, "Please specify a blueprint or create a BlueprintBinding for '%s' in namespace '%s'", obj.Name, obj.Namespace)
 			}
 		}
 	}()
@@ -654,3 +766,53 @@ func max(x, y int) int {
 	}
 	return y
 }
+
+// verifyOptions checks the options of the actions of the ActionSet against
+// the options that their blueprint actions declare.
+func verifyOptions(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, namespace string, as *crv1alpha1.ActionSet) error {
+	blueprintGetter := func(namespace string) kanister.BlueprintGetter {
+		return func(name string) (*crv1alpha1.Blueprint, error) {
+			return crCli.CrV1alpha1().Blueprints(namespace).Get(name, metav1.GetOptions{})
+		}
+	}
+	bps := make(map[string]*crv1alpha1.Blueprint)
+	for _, a := range as.Spec.Actions {
+		bpName := a.Blueprint
+		if bpName == "" {
+			var err error
+			if bpName, err = binding.Resolve(ctx, cli, crCli, namespace, a.Object); err != nil {
+				return err
+			}
+		}
+		bp, ok := bps[bpName]
+		if !ok {
+			var err error
+			bpNamespace, name := blueprintRef(namespace, bpName)
+			if bp, err = blueprintGetter(bpNamespace)(name); err != nil {
+				return errors.Wrapf(err, "Failed to fetch blueprint %s", bpName)
+			}
+			if bp, err = kanister.ExpandBlueprint(bp, blueprintGetter(bpNamespace)); err != nil {
+				return err
+			}
+			bps[bpName] = bp
+		}
+		bpa, ok := bp.Actions[a.Name]
+		if !ok {
+			return errors.Errorf("Action %s not found in blueprint %s", a.Name, bpName)
+		}
+		if _, err := param.ActionOptions(bpa.Options, a.Options); err != nil {
+			return errors.Wrapf(err, "Invalid options for action %s of blueprint %s. See 'kanctl describe blueprint %s'", a.Name, bpName, bpName)
+		}
+	}
+	return nil
+}
+
+// blueprintRef returns the namespace and the name of the Blueprint that an
+// ActionSet in the namespace refers to, either as <name> or as
+// <namespace>/<name>.
+func blueprintRef(namespace, ref string) (string, string) {
+	if i := strings.Index(ref, "/"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return namespace, ref
+}
@@ -0,0 +1,100 @@
+package kanctl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sYAML "k8s.io/apimachinery/pkg/util/yaml"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	"github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/validate"
+)
+
+const (
+	blueprintExpansion          = "Expand the actions used by the Blueprint"
+	blueprintValidation         = "Validate Blueprint functions, phases and template syntax"
+	blueprintTemplateValidation = "Validate Blueprint template fields against template parameters"
+)
+
+func performBlueprintValidation(p *validateParams) error {
+	ctx := context.Background()
+	bp, err := getBlueprintFromCmd(ctx, p)
+	if err != nil {
+		return err
+	}
+	namespace := bp.GetNamespace()
+	if namespace == "" {
+		namespace = p.namespace
+	}
+	if bp, err = kanister.ExpandBlueprint(bp, blueprintGetter(namespace)); err != nil {
+		printStage(blueprintExpansion, fail)
+		return err
+	}
+	return validateBlueprint(bp, p.schemaValidationOnly)
+}
+
+// blueprintGetter returns a getter of the Blueprints in the namespace that
+// only initializes the clients if a Blueprint uses another one.
+func blueprintGetter(namespace string) kanister.BlueprintGetter {
+	var crCli versioned.Interface
+	return func(name string) (*v1alpha1.Blueprint, error) {
+		if crCli == nil {
+			var err error
+			if _, crCli, err = initializeClients(); err != nil {
+				return nil, err
+			}
+		}
+		return crCli.CrV1alpha1().Blueprints(namespace).Get(name, metav1.GetOptions{})
+	}
+}
+
+func validateBlueprint(bp *v1alpha1.Blueprint, schemaValidationOnly bool) error {
+	if err := validate.Blueprint(bp); err != nil {
+		printStage(blueprintValidation, fail)
+		return err
+	}
+	printStage(blueprintValidation, pass)
+	switch {
+	case schemaValidationOnly:
+		printStage(blueprintTemplateValidation, skip)
+	default:
+		if err := validate.BlueprintTemplates(bp); err != nil {
+			printStage(blueprintTemplateValidation, fail)
+			return err
+		}
+		printStage(blueprintTemplateValidation, pass)
+	}
+	printStage(fmt.Sprintf("All checks passed.. %s\n", pass), "")
+	return nil
+}
+
+func getBlueprintFromCmd(ctx context.Context, p *validateParams) (*v1alpha1.Blueprint, error) {
+	if p.name != "" {
+		_, crCli, err := initializeClients()
+		if err != nil {
+			return nil, err
+		}
+		return crCli.CrV1alpha1().Blueprints(p.namespace).Get(p.name, metav1.GetOptions{})
+	}
+	return getBlueprintFromFile(ctx, p.filename)
+}
+
+func getBlueprintFromFile(ctx context.Context, filename string) (*v1alpha1.Blueprint, error) {
+	f := os.Stdin
+	if filename != "-" {
+		var err error
+		if f, err = os.Open(filename); err != nil {
+			return nil, err
+		}
+		defer f.Close()
+	}
+	bp := &v1alpha1.Blueprint{}
+	if err := k8sYAML.NewYAMLOrJSONDecoder(f, 4096).Decode(bp); err != nil {
+		return nil, err
+	}
+	return bp, nil
+}
@@ -0,0 +1,60 @@
+package kanctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/client/clientset/versioned"
+	"github.com/kanisterio/kanister/pkg/reconcile"
+)
+
+func newCancelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a running custom kanister resource",
+	}
+	cmd.AddCommand(newCancelActionSetCmd())
+	return cmd
+}
+
+func newCancelActionSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "actionset <name>",
+		Short: "Cancel a pending or running ActionSet, keeping its status",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return initializeAndCancel(c, args)
+		},
+	}
+	return cmd
+}
+
+func initializeAndCancel(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return newArgsLengthError("expected 1 argument. got %#v", args)
+	}
+	ns, err := resolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+	_, crCli, err := initializeClients()
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	return cancelActionSet(context.Background(), crCli, ns, args[0])
+}
+
+func cancelActionSet(ctx context.Context, crCli versioned.Interface, namespace, name string) error {
+	err := reconcile.ActionSet(ctx, crCli.CrV1alpha1(), namespace, name, func(as *crv1alpha1.ActionSet) error {
+		as.Spec.Cancel = true
+		return nil
+	})
+	if err == nil {
+		fmt.Printf("actionset %s cancellation requested\n", name)
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package kanctl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanister "github.com/kanisterio/kanister/pkg"
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+func newDescribeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe a custom kanister resource",
+	}
+	cmd.AddCommand(newDescribeBlueprintCmd())
+	return cmd
+}
+
+func newDescribeBlueprintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blueprint <name>",
+		Short: "Print the actions of a Blueprint and the options they accept",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return initializeAndDescribeBlueprint(c, args)
+		},
+	}
+	cmd.Flags().StringP(actionFlagName, "a", "", "only describe this action of the blueprint")
+	return cmd
+}
+
+func initializeAndDescribeBlueprint(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return newArgsLengthError("expected 1 argument. got %#v", args)
+	}
+	ns, err := resolveNamespace(cmd)
+	if err != nil {
+		return err
+	}
+	_, crCli, err := initializeClients()
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	getBlueprint := func(name string) (*crv1alpha1.Blueprint, error) {
+		return crCli.CrV1alpha1().Blueprints(ns).Get(name, metav1.GetOptions{})
+	}
+	bp, err := getBlueprint(args[0])
+	if err != nil {
+		return err
+	}
+	if bp, err = kanister.ExpandBlueprint(bp, getBlueprint); err != nil {
+		return err
+	}
+	action, _ := cmd.Flags().GetString(actionFlagName)
+	return describeBlueprint(os.Stdout, bp, action)
+}
+
+// describeBlueprint prints the options of the actions of the Blueprint, or
+// of the given action only.
+func describeBlueprint(w io.Writer, bp *crv1alpha1.Blueprint, action string) error {
+	var names []string
+	switch {
+	case action != "":
+		if _, ok := bp.Actions[action]; !ok {
+			return errors.Errorf("Action %s not found in blueprint %s", action, bp.GetName())
+		}
+		names = []string{action}
+	default:
+		for name := range bp.Actions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		a := bp.Actions[name]
+		if a == nil {
+			continue
+		}
+		fmt.Fprintf(w, "Action: %s\n", name)
+		if a.Kind != "" {
+			fmt.Fprintf(w, "  Kind: %s\n", a.Kind)
+		}
+		if len(a.Options) == 0 {
+			fmt.Fprintf(w, "  Options: <none>\n")
+			continue
+		}
+		opts := make([]string, 0, len(a.Options))
+		for o := range a.Options {
+			opts = append(opts, o)
+		}
+		sort.Strings(opts)
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "  OPTION\tTYPE\tREQUIRED\tDEFAULT\tDESCRIPTION")
+		for _, o := range opts {
+			spec := a.Options[o]
+			typ := spec.Type
+			if typ == "" {
+				typ = crv1alpha1.OptionTypeString
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%t\t%s\t%s\n", o, typ, spec.Required, spec.Default, spec.Description)
+		}
+		if err := tw.Flush(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
@@ -48,6 +48,8 @@ func newRootCommand() *cobra.Command {
 	rootCmd.PersistentFlags().BoolVar(&Verbose, verboseFlagName, false, "Display verbose output")
 	rootCmd.AddCommand(newValidateCommand())
 	rootCmd.AddCommand(newCreateCommand())
+	rootCmd.AddCommand(newCancelCommand())
+	rootCmd.AddCommand(newDescribeCommand())
 	return rootCmd
 }
 
@@ -7,6 +7,8 @@ import (
 	"github.com/kanisterio/kanister/pkg/output"
 )
 
+const outputJSONFlagName = "json"
+
 func newOutputCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "output <key> <value>",
@@ -19,6 +21,7 @@ func newOutputCommand() *cobra.Command {
 			return runOutputCommand(c, args)
 		},
 	}
+	cmd.Flags().Bool(outputJSONFlagName, false, "parse the value as JSON to output a list, a map or a number")
 	return cmd
 }
 
@@ -30,5 +33,8 @@ func validateArguments(c *cobra.Command, args []string) error {
 }
 
 func runOutputCommand(c *cobra.Command, args []string) error {
+	if j, _ := c.Flags().GetBool(outputJSONFlagName); j {
+		return output.PrintOutputJSON(args[0], args[1])
+	}
 	return output.PrintOutput(args[0], args[1])
 }
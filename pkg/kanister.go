@@ -21,6 +21,13 @@ type Func interface {
 	Exec(context.Context, param.TemplateParams, map[string]interface{}) (map[string]interface{}, error)
 }
 
+// FuncOutputs is implemented by functions that always produce the same output
+// keys. It allows references to the output of their phases to be checked
+// before a blueprint is executed.
+type FuncOutputs interface {
+	Outputs() []string
+}
+
 // Register allows Funcs to be references by User Defined YAMLs
 func Register(f Func) error {
 	funcMu.Lock()
@@ -34,3 +41,12 @@ func Register(f Func) error {
 	funcs[f.Name()] = f
 	return nil
 }
+
+// GetFunc returns the function registered with the given name, or false if
+// there is none.
+func GetFunc(name string) (Func, bool) {
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	f, ok := funcs[name]
+	return f, ok
+}
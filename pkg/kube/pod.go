@@ -21,6 +21,7 @@ type PodOptions struct {
 	Command            []string
 	Volumes            map[string]string
 	ServiceAccountName string
+	Labels             map[string]string
 }
 
 // CreatePod creates a pod with a single container based on the specified image
@@ -30,6 +31,7 @@ func CreatePod(ctx context.Context, cli kubernetes.Interface, opts *PodOptions)
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: opts.GenerateName,
 			Namespace:    opts.Namespace,
+			Labels:       opts.Labels,
 		},
 		Spec: v1.PodSpec{
 			Containers: []v1.Container{
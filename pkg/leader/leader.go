@@ -0,0 +1,124 @@
+// Package leader elects the replica of the controller that processes the
+// custom resources, so that several replicas can run for high availability.
+package leader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// LockName is the name of the ConfigMap that holds the leader lock in
+	// the namespace of the controller.
+	LockName = "kanister-controller-leader"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Elector elects a leader among the replicas of the controller that share a
+// lock.
+type Elector struct {
+	cli       kubernetes.Interface
+	recorder  record.EventRecorder
+	namespace string
+	identity  string
+
+	mu      sync.RWMutex
+	leading bool
+	leader  string
+}
+
+// NewElector returns an Elector that competes for the lock in the namespace
+// with the identity, which must be unique among the replicas, such as the
+// name of the pod.
+func NewElector(cli kubernetes.Interface, recorder record.EventRecorder, namespace, identity string) *Elector {
+	return &Elector{
+		cli:       cli,
+		recorder:  recorder,
+		namespace: namespace,
+		identity:  identity,
+	}
+}
+
+// Run blocks until the context is cancelled or the leadership is lost. Once
+// this replica is elected, run is called with a context that is cancelled
+// when it stops leading. The lock is released when the context is cancelled,
+// so that another replica takes over without waiting for the lease to expire.
+func (e *Elector) Run(ctx context.Context, run func(ctx context.Context)) error {
+	lock := &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{
+			Namespace: e.namespace,
+			Name:      LockName,
+		},
+		Client: e.cli.CoreV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity:      e.identity,
+			EventRecorder: e.recorder,
+		},
+	}
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   leaseDuration,
+		RenewDeadline:   renewDeadline,
+		RetryPeriod:     retryPeriod,
+		ReleaseOnCancel: true,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Infof("Started leading as %s", e.identity)
+				e.setLeading(true)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				log.Infof("Stopped leading as %s", e.identity)
+				e.setLeading(false)
+			},
+			OnNewLeader: func(identity string) {
+				log.Infof("New leader elected: %s", identity)
+				e.setLeader(identity)
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to create leader elector")
+	}
+	le.Run(ctx)
+	return nil
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leading = leading
+}
+
+func (e *Elector) setLeader(identity string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = identity
+}
+
+// IsLeader returns true while this replica is the leader.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leading
+}
+
+// Leader returns the identity of the last known leader, which is empty until
+// a leader is observed.
+func (e *Elector) Leader() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
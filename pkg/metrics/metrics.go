@@ -0,0 +1,157 @@
+// Package metrics exposes Prometheus metrics about the controller, the
+// actions it executes and the storage operations of its functions.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+)
+
+const namespace = "kanister"
+
+// Stores whose operations are measured.
+const (
+	StoreBlockStorage = "blockstorage"
+	StoreObjectStore  = "objectstore"
+)
+
+var (
+	phaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "phase_duration_seconds",
+			Help:      "Duration of the phases that were executed, including retries, by function and final state.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
+		},
+		[]string{"function", "state"},
+	)
+	runningActions = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "running_actions",
+			Help:      "Number of actions that are executing.",
+		},
+	)
+	storageDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "storage_operation_duration_seconds",
+			Help:      "Duration of the calls to block storage and object store providers.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+		},
+		[]string{"store", "provider", "operation"},
+	)
+	storageErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "storage_operation_errors_total",
+			Help:      "Number of calls to block storage and object store providers that failed.",
+		},
+		[]string{"store", "provider", "operation"},
+	)
+	lastSuccessDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_successful_action_duration_seconds",
+			Help:      "Duration of the last successful execution of an action on an object.",
+		},
+		actionLabels,
+	)
+	lastSuccessTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_successful_action_timestamp_seconds",
+			Help:      "Time at which the last successful execution of an action on an object completed.",
+		},
+		actionLabels,
+	)
+	actionSetsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "actionsets"),
+		"Number of ActionSets by state.",
+		[]string{"state"}, nil,
+	)
+	phasesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "phases"),
+		"Number of phases of ActionSets by state.",
+		[]string{"state"}, nil,
+	)
+)
+
+var actionLabels = []string{"blueprint", "action", "namespace", "kind", "name"}
+
+func init() {
+	prometheus.MustRegister(phaseDuration, runningActions, storageDuration, storageErrors, lastSuccessDuration, lastSuccessTime)
+}
+
+// Handler returns the handler that serves the metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// StateCounter returns the number of ActionSets and of their phases by
+// state.
+type StateCounter func() (actionSets map[string]int, phases map[string]int, err error)
+
+// RegisterStateCounter registers a StateCounter that is called whenever the
+// metrics are collected. Only the first StateCounter is registered.
+func RegisterStateCounter(sc StateCounter) {
+	err := prometheus.Register(stateCollector(sc))
+	if _, ok := err.(prometheus.AlreadyRegisteredError); err != nil && !ok {
+		log.Errorf("Failed to register the ActionSet metrics: %+v", err)
+	}
+}
+
+type stateCollector StateCounter
+
+func (stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- actionSetsDesc
+	ch <- phasesDesc
+}
+
+func (sc stateCollector) Collect(ch chan<- prometheus.Metric) {
+	actionSets, phases, err := sc()
+	if err != nil {
+		log.Errorf("Failed to count ActionSets by state: %+v", err)
+		ch <- prometheus.NewInvalidMetric(actionSetsDesc, err)
+		return
+	}
+	for state, n := range actionSets {
+		ch <- prometheus.MustNewConstMetric(actionSetsDesc, prometheus.GaugeValue, float64(n), state)
+	}
+	for state, n := range phases {
+		ch <- prometheus.MustNewConstMetric(phasesDesc, prometheus.GaugeValue, float64(n), state)
+	}
+}
+
+// ObservePhase records the duration of a phase that executed a function and
+// ended in the given state.
+func ObservePhase(function, state string, d time.Duration) {
+	phaseDuration.WithLabelValues(function, state).Observe(d.Seconds())
+}
+
+// ActionStarted records that an action started executing. The returned
+// function must be called once it stops.
+func ActionStarted() func() {
+	runningActions.Inc()
+	return runningActions.Dec
+}
+
+// ActionSucceeded records the duration and the completion time of the
+// successful execution of the action of a Blueprint on an object.
+func ActionSucceeded(blueprint, action, namespace, kind, name string, start, end time.Time) {
+	lastSuccessDuration.WithLabelValues(blueprint, action, namespace, kind, name).Set(end.Sub(start).Seconds())
+	lastSuccessTime.WithLabelValues(blueprint, action, namespace, kind, name).Set(float64(end.Unix()))
+}
+
+// ObserveStorage records the duration of a call to a storage provider that
+// started at start, and whether it failed.
+func ObserveStorage(store, provider, operation string, start time.Time, err error) {
+	storageDuration.WithLabelValues(store, provider, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		storageErrors.WithLabelValues(store, provider, operation).Inc()
+	}
+}
@@ -9,6 +9,9 @@ func GetOrCreateBucket(ctx context.Context, p Provider, bucketName string, regio
 
 // IsS3Provider is a helper function to find out if a provider is an s3Provider
 func IsS3Provider(p Provider) bool {
+	if mp, ok := p.(*metricsProvider); ok {
+		p = mp.Provider
+	}
 	if _, ok := p.(*s3Provider); ok {
 		return true
 	}
@@ -0,0 +1,166 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/kanisterio/kanister/pkg/metrics"
+)
+
+var _ Provider = (*metricsProvider)(nil)
+
+// metricsProvider records the latency and the errors of the calls to a
+// Provider and to the buckets and directories it returns.
+type metricsProvider struct {
+	Provider
+	typ ProviderType
+}
+
+func (p *metricsProvider) observe(operation string, start time.Time, err error) {
+	metrics.ObserveStorage(metrics.StoreObjectStore, string(p.typ), operation, start, err)
+}
+
+func (p *metricsProvider) bucket(b Bucket) Bucket {
+	if b == nil {
+		return nil
+	}
+	return &metricsDirectory{Directory: b, typ: p.typ}
+}
+
+func (p *metricsProvider) CreateBucket(ctx context.Context, bucketName, region string) (Bucket, error) {
+	start := time.Now()
+	b, err := p.Provider.CreateBucket(ctx, bucketName, region)
+	p.observe("CreateBucket", start, err)
+	return p.bucket(b), err
+}
+
+func (p *metricsProvider) GetBucket(ctx context.Context, bucketName string) (Bucket, error) {
+	start := time.Now()
+	b, err := p.Provider.GetBucket(ctx, bucketName)
+	p.observe("GetBucket", start, err)
+	return p.bucket(b), err
+}
+
+func (p *metricsProvider) DeleteBucket(ctx context.Context, bucketName string) error {
+	start := time.Now()
+	err := p.Provider.DeleteBucket(ctx, bucketName)
+	p.observe("DeleteBucket", start, err)
+	return err
+}
+
+func (p *metricsProvider) ListBuckets(ctx context.Context) (map[string]Bucket, error) {
+	start := time.Now()
+	bs, err := p.Provider.ListBuckets(ctx)
+	p.observe("ListBuckets", start, err)
+	for name, b := range bs {
+		bs[name] = p.bucket(b)
+	}
+	return bs, err
+}
+
+func (p *metricsProvider) getOrCreateBucket(ctx context.Context, bucketName, region string) (Bucket, error) {
+	start := time.Now()
+	b, err := p.Provider.getOrCreateBucket(ctx, bucketName, region)
+	p.observe("GetOrCreateBucket", start, err)
+	return p.bucket(b), err
+}
+
+var _ Bucket = (*metricsDirectory)(nil)
+
+type metricsDirectory struct {
+	Directory
+	typ ProviderType
+}
+
+func (d *metricsDirectory) observe(operation string, start time.Time, err error) {
+	metrics.ObserveStorage(metrics.StoreObjectStore, string(d.typ), operation, start, err)
+}
+
+func (d *metricsDirectory) directory(dir Directory) Directory {
+	if dir == nil {
+		return nil
+	}
+	return &metricsDirectory{Directory: dir, typ: d.typ}
+}
+
+func (d *metricsDirectory) CreateDirectory(ctx context.Context, dir string) (Directory, error) {
+	start := time.Now()
+	cd, err := d.Directory.CreateDirectory(ctx, dir)
+	d.observe("CreateDirectory", start, err)
+	return d.directory(cd), err
+}
+
+func (d *metricsDirectory) GetDirectory(ctx context.Context, dir string) (Directory, error) {
+	start := time.Now()
+	gd, err := d.Directory.GetDirectory(ctx, dir)
+	d.observe("GetDirectory", start, err)
+	return d.directory(gd), err
+}
+
+func (d *metricsDirectory) DeleteDirectory(ctx context.Context) error {
+	start := time.Now()
+	err := d.Directory.DeleteDirectory(ctx)
+	d.observe("DeleteDirectory", start, err)
+	return err
+}
+
+func (d *metricsDirectory) DeleteAllWithPrefix(ctx context.Context, prefix string) error {
+	start := time.Now()
+	err := d.Directory.DeleteAllWithPrefix(ctx, prefix)
+	d.observe("DeleteAllWithPrefix", start, err)
+	return err
+}
+
+func (d *metricsDirectory) ListDirectories(ctx context.Context) (map[string]Directory, error) {
+	start := time.Now()
+	dirs, err := d.Directory.ListDirectories(ctx)
+	d.observe("ListDirectories", start, err)
+	for name, dir := range dirs {
+		dirs[name] = d.directory(dir)
+	}
+	return dirs, err
+}
+
+func (d *metricsDirectory) ListObjects(ctx context.Context) ([]string, error) {
+	start := time.Now()
+	objs, err := d.Directory.ListObjects(ctx)
+	d.observe("ListObjects", start, err)
+	return objs, err
+}
+
+// Get only measures the time to open the object, not to read it.
+func (d *metricsDirectory) Get(ctx context.Context, name string) (io.ReadCloser, map[string]string, error) {
+	start := time.Now()
+	r, tags, err := d.Directory.Get(ctx, name)
+	d.observe("Get", start, err)
+	return r, tags, err
+}
+
+func (d *metricsDirectory) GetBytes(ctx context.Context, name string) ([]byte, map[string]string, error) {
+	start := time.Now()
+	data, tags, err := d.Directory.GetBytes(ctx, name)
+	d.observe("GetBytes", start, err)
+	return data, tags, err
+}
+
+func (d *metricsDirectory) Put(ctx context.Context, name string, r io.Reader, size int64, tags map[string]string) error {
+	start := time.Now()
+	err := d.Directory.Put(ctx, name, r, size, tags)
+	d.observe("Put", start, err)
+	return err
+}
+
+func (d *metricsDirectory) PutBytes(ctx context.Context, name string, data []byte, tags map[string]string) error {
+	start := time.Now()
+	err := d.Directory.PutBytes(ctx, name, data, tags)
+	d.observe("PutBytes", start, err)
+	return err
+}
+
+func (d *metricsDirectory) Delete(ctx context.Context, name string) error {
+	start := time.Now()
+	err := d.Directory.Delete(ctx, name)
+	d.observe("Delete", start, err)
+	return err
+}
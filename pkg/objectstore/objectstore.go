@@ -79,7 +79,8 @@ type Directory interface {
 	String() string
 }
 
-// NewProvider creates a new Provider
+// NewProvider creates a new Provider. The latency and the errors of the calls
+// to the provider and to its buckets and directories are recorded as metrics.
 func NewProvider(ctx context.Context, config ProviderConfig, secret *Secret) (Provider, error) {
 	p := &provider{
 		hostEndPoint: getHostURI(config),
@@ -87,9 +88,9 @@ func NewProvider(ctx context.Context, config ProviderConfig, secret *Secret) (Pr
 		secret:       secret,
 	}
 	if p.config.Type == ProviderTypeS3 {
-		return &s3Provider{provider: p}, nil
+		return &metricsProvider{Provider: &s3Provider{provider: p}, typ: config.Type}, nil
 	}
-	return p, nil
+	return &metricsProvider{Provider: p, typ: config.Type}, nil
 }
 
 // Supported returns true if the object store type is supported
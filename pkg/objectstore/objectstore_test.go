@@ -495,6 +495,9 @@ func cleanupBucketDirectory(c *C, bucket Bucket, directory string) {
 // getStowContainer checks that the given directory matches the implementation
 // type
 func getStowContainer(c *C, d Directory) stow.Container {
+	if md, ok := d.(*metricsDirectory); ok {
+		d = md.Directory
+	}
 	c.Assert(d, FitsTypeOf, &directory{})
 	sd, ok := d.(*directory)
 	c.Assert(ok, Equals, true)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -12,12 +13,15 @@ const (
 	PhaseOpString = "###Phase-output###:"
 )
 
+// Output is a key-value pair of the output of a phase. The value is a string,
+// or a structured value such as a list, a map or a number if it was printed
+// with PrintOutputJSON.
 type Output struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
 }
 
-func marshalOutput(key, value string) (string, error) {
+func marshalOutput(key string, value interface{}) (string, error) {
 	out := &Output{
 		Key:   key,
 		Value: value,
@@ -29,10 +33,13 @@ func marshalOutput(key, value string) (string, error) {
 	return string(outString), nil
 }
 
-// UnmarshalOutput unmarshals output json into Output struct
+// UnmarshalOutput unmarshals output json into Output struct. Numbers are
+// unmarshalled as json.Number so that they keep their precision.
 func UnmarshalOutput(opString string) (*Output, error) {
 	p := &Output{}
-	err := json.Unmarshal([]byte(opString), p)
+	dec := json.NewDecoder(strings.NewReader(opString))
+	dec.UseNumber()
+	err := dec.Decode(p)
 	return p, errors.Wrap(err, "Failed to unmarshal key-value pair")
 }
 
@@ -59,3 +66,31 @@ func PrintOutput(key, value string) error {
 	fmt.Println(PhaseOpString, outString)
 	return nil
 }
+
+// PrintOutputJSON runs the `kando output --json` command. The value must be
+// valid JSON and is output as a structured value rather than as a string.
+func PrintOutputJSON(key, value string) error {
+	v, err := unmarshalValue(value)
+	if err != nil {
+		return err
+	}
+	outString, err := marshalOutput(key, v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(PhaseOpString, outString)
+	return nil
+}
+
+func unmarshalValue(value string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Wrap(err, "Value should be valid JSON")
+	}
+	if dec.More() {
+		return nil, errors.New("Value should be a single JSON value")
+	}
+	return v, nil
+}
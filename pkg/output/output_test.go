@@ -1,6 +1,7 @@
 package output
 
 import (
+	"encoding/json"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -29,3 +30,29 @@ func (s *OutputSuite) TestValidateKey(c *C) {
 		c.Check(err, tc.checker, Commentf("Key (%s) failed!", tc.key))
 	}
 }
+
+func (s *OutputSuite) TestOutputJSON(c *C) {
+	for _, tc := range []struct {
+		value   string
+		out     interface{}
+		checker Checker
+	}{
+		{`["a1", "b2"]`, []interface{}{"a1", "b2"}, IsNil},
+		{`{"id": 12345678901234567890}`, map[string]interface{}{"id": json.Number("12345678901234567890")}, IsNil},
+		{`"path"`, "path", IsNil},
+		{`path`, nil, NotNil},
+		{`["a1"] ["b2"]`, nil, NotNil},
+	} {
+		v, err := unmarshalValue(tc.value)
+		c.Assert(err, tc.checker, Commentf("Value (%s) failed!", tc.value))
+		if err != nil {
+			continue
+		}
+		m, err := marshalOutput("key", v)
+		c.Assert(err, IsNil)
+		o, err := UnmarshalOutput(m)
+		c.Assert(err, IsNil)
+		c.Assert(o.Key, Equals, "key")
+		c.Assert(o.Value, DeepEquals, tc.out)
+	}
+}
@@ -0,0 +1,220 @@
+package param
+
+import (
+	"context"
+	"sort"
+	"text/template/parse"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// ArtifactSecretKey returns the key of the Secret that stores the value of a
+// sensitive key of an artifact.
+func ArtifactSecretKey(artifact, key string) string {
+	return artifact + "." + key
+}
+
+// SplitSensitiveArtifacts returns copies of the rendered artifacts without
+// the values of their sensitive keys, and those values by ArtifactSecretKey.
+// The artifacts that have sensitive keys refer to the Secret ref, which is
+// expected to store the values.
+func SplitSensitiveArtifacts(arts map[string]crv1alpha1.Artifact, ref crv1alpha1.ObjectReference) (map[string]crv1alpha1.Artifact, map[string][]byte) {
+	sarts := make(map[string]crv1alpha1.Artifact, len(arts))
+	data := make(map[string][]byte)
+	for name, a := range arts {
+		sa := *a.DeepCopy()
+		if len(a.Sensitive) > 0 {
+			for _, k := range a.Sensitive {
+				v, ok := sa.KeyValue[k]
+				if !ok {
+					continue
+				}
+				data[ArtifactSecretKey(name, k)] = []byte(v)
+				delete(sa.KeyValue, k)
+			}
+			r := ref
+			sa.SecretRef = &r
+		}
+		sarts[name] = sa
+	}
+	return sarts, data
+}
+
+// fetchArtifacts returns copies of the artifacts in which the values of the
+// sensitive keys are read from the Secrets that the artifacts refer to. Only
+// Secrets that are controlled by an ActionSet are read, so that artifacts
+// cannot be used to read arbitrary Secrets.
+func fetchArtifacts(ctx context.Context, cli kubernetes.Interface, arts map[string]crv1alpha1.Artifact) (map[string]crv1alpha1.Artifact, error) {
+	resolved := false
+	for _, a := range arts {
+		if a.SecretRef != nil && len(a.Sensitive) > 0 {
+			resolved = true
+			break
+		}
+	}
+	if !resolved {
+		return arts, nil
+	}
+	farts := make(map[string]crv1alpha1.Artifact, len(arts))
+	for name, a := range arts {
+		fa := *a.DeepCopy()
+		if a.SecretRef != nil && len(a.Sensitive) > 0 {
+			s, err := cli.CoreV1().Secrets(a.SecretRef.Namespace).Get(a.SecretRef.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to fetch the sensitive values of artifact %s", name)
+			}
+			if !controlledByActionSet(s.ObjectMeta) {
+				return nil, errors.Errorf("Secret '%s:%s' of artifact %s is not controlled by an ActionSet", s.GetNamespace(), s.GetName(), name)
+			}
+			if fa.KeyValue == nil {
+				fa.KeyValue = make(map[string]string, len(a.Sensitive))
+			}
+			for _, k := range a.Sensitive {
+				v, ok := s.Data[ArtifactSecretKey(name, k)]
+				if !ok {
+					return nil, errors.Errorf("Value of key %s of artifact %s not found in secret '%s:%s'", k, name, s.GetNamespace(), s.GetName())
+				}
+				fa.KeyValue[k] = string(v)
+			}
+		}
+		farts[name] = fa
+	}
+	return farts, nil
+}
+
+// controlledByActionSet returns whether the object is controlled by an
+// ActionSet.
+func controlledByActionSet(m metav1.ObjectMeta) bool {
+	ref := metav1.GetControllerOf(&m)
+	return ref != nil && ref.Kind == crv1alpha1.ActionSetResource.Kind && ref.APIVersion == crv1alpha1.SchemeGroupVersion.String()
+}
+
+// SensitiveOutputs returns the output keys of the phases that the templates
+// of the sensitive keys of the artifacts refer to. A nil slice means that a
+// template refers to the whole output of the phase, or to a key that is only
+// known when it is rendered.
+func SensitiveOutputs(arts map[string]crv1alpha1.Artifact) (PhaseOutputs, error) {
+	refs := make(PhaseOutputs)
+	for name, a := range arts {
+		for _, k := range a.Sensitive {
+			v, ok := a.KeyValue[k]
+			if !ok {
+				continue
+			}
+			t, err := parseStringArg(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to parse key %s of artifact %s", k, name)
+			}
+			if t.Tree != nil {
+				addOutputRefs(t.Tree.Root, refs)
+			}
+		}
+	}
+	for _, keys := range refs {
+		sort.Strings(keys)
+	}
+	return refs, nil
+}
+
+func addOutputRefs(node parse.Node, refs PhaseOutputs) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			addOutputRefs(c, refs)
+		}
+	case *parse.ActionNode:
+		addOutputRefs(n.Pipe, refs)
+	case *parse.IfNode:
+		addBranchOutputRefs(&n.BranchNode, refs)
+	case *parse.RangeNode:
+		addBranchOutputRefs(&n.BranchNode, refs)
+	case *parse.WithNode:
+		addBranchOutputRefs(&n.BranchNode, refs)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			addOutputRefs(n.Pipe, refs)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			addOutputRefs(c, refs)
+		}
+	case *parse.CommandNode:
+		args := n.Args
+		// index .Phases.<name>.Output "key"
+		if len(args) >= 3 && isIdentifier(args[0], "index") {
+			if phase, ok := outputPhase(args[1]); ok {
+				if s, ok := args[2].(*parse.StringNode); ok {
+					addOutputKey(refs, phase, s.Text)
+					args = args[3:]
+				}
+			}
+		}
+		for _, a := range args {
+			addOutputRefs(a, refs)
+		}
+	case *parse.ChainNode:
+		addOutputRefs(n.Node, refs)
+	case *parse.FieldNode:
+		addIdentOutputRefs(n.Ident, 0, refs)
+	case *parse.VariableNode:
+		addIdentOutputRefs(n.Ident, 1, refs)
+	}
+}
+
+func addBranchOutputRefs(n *parse.BranchNode, refs PhaseOutputs) {
+	addOutputRefs(n.Pipe, refs)
+	addOutputRefs(n.List, refs)
+	addOutputRefs(n.ElseList, refs)
+}
+
+// addIdentOutputRefs adds the reference of a chain of fields to
+// .Phases.<name>.Output at position i.
+func addIdentOutputRefs(ident []string, i int, refs PhaseOutputs) {
+	if len(ident) < i+3 || ident[i] != "Phases" || ident[i+2] != "Output" {
+		return
+	}
+	if len(ident) == i+3 {
+		refs[ident[i+1]] = nil
+		return
+	}
+	addOutputKey(refs, ident[i+1], ident[i+3])
+}
+
+// outputPhase returns the phase whose output the node is, if any.
+func outputPhase(node parse.Node) (string, bool) {
+	var ident []string
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		ident = n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) == 0 || n.Ident[0] != "$" {
+			return "", false
+		}
+		ident = n.Ident[1:]
+	}
+	if len(ident) != 3 || ident[0] != "Phases" || ident[2] != "Output" {
+		return "", false
+	}
+	return ident[1], true
+}
+
+func addOutputKey(refs PhaseOutputs, phase, key string) {
+	keys, ok := refs[phase]
+	switch {
+	case ok && keys == nil:
+		// The whole output is already referred to.
+	case contains(keys, key):
+	default:
+		refs[phase] = append(keys, key)
+	}
+}
@@ -0,0 +1,141 @@
+package param
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type ArtifactsSuite struct{}
+
+var _ = Suite(&ArtifactsSuite{})
+
+func (s *ArtifactsSuite) TestSensitiveArtifacts(c *C) {
+	tpls := map[string]crv1alpha1.Artifact{
+		"backup": {
+			KeyValue: map[string]string{
+				"path":          "/backups/{{ .Options.name }}",
+				"encryptionKey": "{{ .Options.key }}",
+			},
+			Sensitive: []string{"encryptionKey"},
+		},
+		"manifest": {
+			KeyValue: map[string]string{"path": "/manifests/{{ .Options.name }}"},
+		},
+	}
+	arts, err := RenderArtifacts(tpls, TemplateParams{Options: map[string]string{"name": "db", "key": "s3cr3t"}})
+	c.Assert(err, IsNil)
+	c.Assert(arts["backup"].Sensitive, DeepEquals, []string{"encryptionKey"})
+
+	ref := crv1alpha1.ObjectReference{Kind: "Secret", Name: "backup-artifacts-0", Namespace: "ns"}
+	sarts, data := SplitSensitiveArtifacts(arts, ref)
+	c.Assert(data, DeepEquals, map[string][]byte{"backup.encryptionKey": []byte("s3cr3t")})
+	c.Assert(sarts["backup"].KeyValue, DeepEquals, map[string]string{"path": "/backups/db"})
+	c.Assert(sarts["backup"].SecretRef, DeepEquals, &ref)
+	c.Assert(sarts["manifest"], DeepEquals, arts["manifest"])
+	// The rendered artifacts are not modified.
+	c.Assert(arts["backup"].KeyValue["encryptionKey"], Equals, "s3cr3t")
+
+	isController := true
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ref.Name,
+			Namespace: ref.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: crv1alpha1.SchemeGroupVersion.String(),
+					Kind:       crv1alpha1.ActionSetResource.Kind,
+					Name:       "backup",
+					Controller: &isController,
+				},
+			},
+		},
+		Data: data,
+	}
+	cli := fake.NewSimpleClientset(secret)
+	farts, err := fetchArtifacts(context.Background(), cli, sarts)
+	c.Assert(err, IsNil)
+	c.Assert(farts["backup"].KeyValue, DeepEquals, arts["backup"].KeyValue)
+	c.Assert(farts["manifest"], DeepEquals, arts["manifest"])
+	// The artifacts of the ActionSpec are not modified.
+	c.Assert(sarts["backup"].KeyValue, HasLen, 1)
+
+	// The values must be found in the secret.
+	_, err = fetchArtifacts(context.Background(), fake.NewSimpleClientset(), sarts)
+	c.Assert(err, NotNil)
+	// Secrets that are not controlled by an ActionSet are not read.
+	userSecret := secret.DeepCopy()
+	userSecret.OwnerReferences = nil
+	_, err = fetchArtifacts(context.Background(), fake.NewSimpleClientset(userSecret), sarts)
+	c.Assert(err, NotNil)
+	secret.Data = nil
+	_, err = fetchArtifacts(context.Background(), fake.NewSimpleClientset(secret), sarts)
+	c.Assert(err, NotNil)
+
+	// Artifacts without sensitive values are passed through.
+	farts, err = fetchArtifacts(context.Background(), fake.NewSimpleClientset(), tpls)
+	c.Assert(err, IsNil)
+	c.Assert(farts, DeepEquals, tpls)
+}
+
+func (s *ArtifactsSuite) TestSensitiveOutputs(c *C) {
+	for _, tc := range []struct {
+		kvs       map[string]string
+		sensitive []string
+		refs      PhaseOutputs
+		checker   Checker
+	}{
+		{
+			kvs: map[string]string{
+				"path":          "{{ .Phases.backup.Output.path }}",
+				"encryptionKey": "{{ .Phases.backup.Output.key }}",
+			},
+			sensitive: []string{"encryptionKey"},
+			refs:      PhaseOutputs{"backup": {"key"}},
+			checker:   IsNil,
+		},
+		{
+			kvs: map[string]string{
+				"creds": `{{ index .Phases.backup.Output "password" }}:{{ $.Phases.backup.Output.user | b64enc }}`,
+				"all":   "{{ toJson .Phases.dump.Output }}",
+			},
+			sensitive: []string{"creds", "all", "missing"},
+			refs:      PhaseOutputs{"backup": {"password", "user"}, "dump": nil},
+			checker:   IsNil,
+		},
+		{
+			kvs: map[string]string{
+				"key": "{{ if .Phases.backup.Output.key }}{{ .Phases.backup.Output.key }}{{ end }}",
+			},
+			sensitive: []string{"key"},
+			refs:      PhaseOutputs{"backup": {"key"}},
+			checker:   IsNil,
+		},
+		{
+			kvs: map[string]string{
+				"key": "{{ .Phases.backup.Output.key }}",
+			},
+			refs:    PhaseOutputs{},
+			checker: IsNil,
+		},
+		{
+			kvs: map[string]string{
+				"key": "{{ .Phases.backup.Output.key ",
+			},
+			sensitive: []string{"key"},
+			checker:   NotNil,
+		},
+	} {
+		arts := map[string]crv1alpha1.Artifact{
+			"cloudObject": {KeyValue: tc.kvs, Sensitive: tc.sensitive},
+		}
+		refs, err := SensitiveOutputs(arts)
+		c.Check(err, tc.checker)
+		c.Check(refs, DeepEquals, tc.refs)
+	}
+}
@@ -0,0 +1,305 @@
+package param
+
+import (
+	"reflect"
+	"text/template/parse"
+
+	"github.com/pkg/errors"
+)
+
+// PhaseOutputs maps the names of the phases that a template may refer to
+// onto the output keys that they declare. A nil slice means that the phase
+// does not declare its output keys, so any key is accepted.
+type PhaseOutputs map[string][]string
+
+var (
+	templateParamsType = reflect.TypeOf(TemplateParams{})
+	phasesType         = reflect.TypeOf(map[string]*Phase{})
+)
+
+// CheckTemplates statically checks the field references of every string
+// template in arg against TemplateParams, recursing like ParseTemplates.
+// References to .Phases must name one of the given phases and, if the phase
+// declares them, one of its output keys. Values whose type is only known when
+// the template is rendered, such as the output of functions, are not checked.
+func CheckTemplates(arg interface{}, phases PhaseOutputs) error {
+	if arg == nil {
+		return nil
+	}
+	val := reflect.ValueOf(arg)
+	switch val.Kind() {
+	case reflect.String:
+		return checkStringArg(val.String(), phases)
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			if err := CheckTemplates(val.Index(i).Interface(), phases); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, k := range val.MapKeys() {
+			if err := CheckTemplates(k.Interface(), phases); err != nil {
+				return err
+			}
+			if err := CheckTemplates(val.MapIndex(k).Interface(), phases); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if err := CheckTemplates(val.Field(i).Interface(), phases); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkStringArg(arg string, phases PhaseOutputs) error {
+	t, err := parseStringArg(arg)
+	if err != nil {
+		return err
+	}
+	if t.Tree == nil {
+		return nil
+	}
+	tc := &templateChecker{
+		tree:   t.Tree,
+		phases: phases,
+		vars:   map[string]reflect.Type{"$": templateParamsType},
+	}
+	if err := tc.walk(t.Tree.Root, templateParamsType); err != nil {
+		return errors.Wrapf(err, "Invalid template {%s}", arg)
+	}
+	return nil
+}
+
+// templateChecker follows the type of dot and of variables through a parsed
+// template. A nil type is unknown and is not checked.
+type templateChecker struct {
+	tree   *parse.Tree
+	phases PhaseOutputs
+	vars   map[string]reflect.Type
+}
+
+func (tc *templateChecker) walk(node parse.Node, dot reflect.Type) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := tc.walk(c, dot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		_, err := tc.pipe(n.Pipe, dot)
+		return err
+	case *parse.IfNode:
+		if _, err := tc.pipe(n.Pipe, dot); err != nil {
+			return err
+		}
+		return tc.branches(n.List, dot, n.ElseList, dot)
+	case *parse.WithNode:
+		typ, err := tc.pipe(n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+		return tc.branches(n.List, typ, n.ElseList, dot)
+	case *parse.RangeNode:
+		typ, err := tc.pipeType(n.Pipe, dot)
+		if err != nil {
+			return err
+		}
+		key, elem := rangeTypes(typ)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			tc.vars[n.Pipe.Decl[0].Ident[0]] = elem
+		case 2:
+			tc.vars[n.Pipe.Decl[0].Ident[0]] = key
+			tc.vars[n.Pipe.Decl[1].Ident[0]] = elem
+		}
+		return tc.branches(n.List, elem, n.ElseList, dot)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			_, err := tc.pipe(n.Pipe, dot)
+			return err
+		}
+	}
+	return nil
+}
+
+// branches walks the lists of a control structure. Variables declared inside
+// them go out of scope at its end.
+func (tc *templateChecker) branches(list *parse.ListNode, listDot reflect.Type, elseList *parse.ListNode, elseDot reflect.Type) error {
+	vars := make(map[string]reflect.Type, len(tc.vars))
+	for k, v := range tc.vars {
+		vars[k] = v
+	}
+	defer func() { tc.vars = vars }()
+	if err := tc.walk(list, listDot); err != nil {
+		return err
+	}
+	return tc.walk(elseList, elseDot)
+}
+
+func (tc *templateChecker) pipe(p *parse.PipeNode, dot reflect.Type) (reflect.Type, error) {
+	typ, err := tc.pipeType(p, dot)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range p.Decl {
+		tc.vars[v.Ident[0]] = typ
+	}
+	return typ, nil
+}
+
+func (tc *templateChecker) pipeType(p *parse.PipeNode, dot reflect.Type) (reflect.Type, error) {
+	if p == nil {
+		return nil, nil
+	}
+	var typ reflect.Type
+	for i, cmd := range p.Cmds {
+		var err error
+		typ, err = tc.command(cmd, dot)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			// The result of the previous command is passed as the
+			// final argument, so the result is only known at runtime.
+			typ = nil
+		}
+	}
+	return typ, nil
+}
+
+func (tc *templateChecker) command(cmd *parse.CommandNode, dot reflect.Type) (reflect.Type, error) {
+	var typ reflect.Type
+	for i, arg := range cmd.Args {
+		t, err := tc.arg(arg, dot)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			typ = t
+		}
+	}
+	return typ, nil
+}
+
+func (tc *templateChecker) arg(node parse.Node, dot reflect.Type) (reflect.Type, error) {
+	var typ reflect.Type
+	var fields []string
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot, nil
+	case *parse.FieldNode:
+		typ, fields = dot, n.Ident
+	case *parse.VariableNode:
+		typ, fields = tc.vars[n.Ident[0]], n.Ident[1:]
+	case *parse.ChainNode:
+		var err error
+		if typ, err = tc.arg(n.Node, dot); err != nil {
+			return nil, err
+		}
+		fields = n.Field
+	case *parse.PipeNode:
+		return tc.pipeType(n, dot)
+	default:
+		return nil, nil
+	}
+	typ, err := tc.fields(typ, fields)
+	if err != nil {
+		loc, _ := tc.tree.ErrorContext(node)
+		return nil, errors.Errorf("%s: %s", loc, err)
+	}
+	return typ, nil
+}
+
+func (tc *templateChecker) fields(typ reflect.Type, fields []string) (reflect.Type, error) {
+	for i, f := range fields {
+		if typ == nil {
+			return nil, nil
+		}
+		if typ == phasesType {
+			keys, ok := tc.phases[f]
+			if !ok {
+				return nil, errors.Errorf("phase %s has not completed before this template is rendered", f)
+			}
+			if i+2 < len(fields) && fields[i+1] == "Output" && keys != nil && !contains(keys, fields[i+2]) {
+				return nil, errors.Errorf("phase %s does not output %s", f, fields[i+2])
+			}
+		}
+		var err error
+		if typ, err = fieldType(typ, f); err != nil {
+			return nil, err
+		}
+	}
+	return typ, nil
+}
+
+// fieldType returns the type of the named field, method or map key of typ,
+// like it is evaluated by text/template.
+func fieldType(typ reflect.Type, name string) (reflect.Type, error) {
+	if m, ok := typ.MethodByName(name); ok {
+		return methodResult(m.Type), nil
+	}
+	if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+		if m, ok := reflect.PtrTo(typ).MethodByName(name); ok {
+			return methodResult(m.Type), nil
+		}
+	}
+	t := typ
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if sf, ok := t.FieldByName(name); ok && sf.PkgPath == "" {
+			return sf.Type, nil
+		}
+	case reflect.Map:
+		if reflect.TypeOf(name).AssignableTo(t.Key()) {
+			return t.Elem(), nil
+		}
+	case reflect.Interface:
+		return nil, nil
+	}
+	return nil, errors.Errorf("can't evaluate field %s in type %s", name, typ)
+}
+
+func methodResult(m reflect.Type) reflect.Type {
+	if m.NumOut() == 0 {
+		return nil
+	}
+	return m.Out(0)
+}
+
+func rangeTypes(typ reflect.Type) (key, elem reflect.Type) {
+	if typ == nil {
+		return nil, nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeOf(0), typ.Elem()
+	case reflect.Map:
+		return typ.Key(), typ.Elem()
+	case reflect.Chan:
+		return nil, typ.Elem()
+	}
+	return nil, nil
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
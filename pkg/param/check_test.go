@@ -0,0 +1,53 @@
+package param
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type CheckSuite struct{}
+
+var _ = Suite(&CheckSuite{})
+
+func (s *CheckSuite) TestCheckTemplates(c *C) {
+	phases := PhaseOutputs{
+		"backup":   nil,
+		"snapshot": []string{"volumeSnapshotInfo"},
+	}
+	for _, tc := range []struct {
+		arg     interface{}
+		checker Checker
+	}{
+		{arg: "", checker: IsNil},
+		{arg: "plain", checker: IsNil},
+		{arg: "{{ .StatefulSet.Name }}", checker: IsNil},
+		{arg: "{{ .StatefulSet.Podz }}", checker: NotNil},
+		{arg: "{{ .Options.foo }}", checker: IsNil},
+		{arg: "{{ .Options.foo.bar }}", checker: NotNil},
+		{arg: "{{ .Object.spec.replicas }}", checker: IsNil},
+		{arg: "{{ .ArtifactsIn.cloudObject.KeyValue.path }}", checker: IsNil},
+		{arg: "{{ .ArtifactsIn.cloudObject.Keyvalue.path }}", checker: NotNil},
+		{arg: "{{ .Secrets.creds.Data.password | toString }}", checker: IsNil},
+		{arg: "{{ .Phases.backup.Output.anything }}", checker: IsNil},
+		{arg: "{{ .Phases.snapshot.Output.volumeSnapshotInfo }}", checker: IsNil},
+		{arg: "{{ .Phases.snapshot.Output.volumeSnapshot }}", checker: NotNil},
+		{arg: "{{ .Phases.restore.Output.path }}", checker: NotNil},
+		{arg: "{{ .Phases.backup.Outputs }}", checker: NotNil},
+		{arg: "{{ range .StatefulSet.Pods }}{{ . }}{{ end }}", checker: IsNil},
+		{arg: "{{ range $i, $pod := .StatefulSet.Pods }}{{ $pod.Name }}{{ end }}", checker: NotNil},
+		{arg: "{{ range $pvc, $path := .Deployment.PersistentVolumeClaims }}{{ $path.foo }}{{ end }}", checker: IsNil},
+		{arg: "{{ with .Profile }}{{ .Location.Bucket }}{{ end }}", checker: IsNil},
+		{arg: "{{ with .Profile }}{{ .Bucket }}{{ end }}", checker: NotNil},
+		{arg: "{{ with .Profile }}{{ .Location.Bucket }}{{ else }}{{ .Namespace.Name }}{{ end }}", checker: IsNil},
+		{arg: "{{ $ns := .Namespace }}{{ $ns.Nme }}", checker: NotNil},
+		{arg: "{{ with .Profile }}{{ $.Namespace.Name }}{{ end }}", checker: IsNil},
+		{arg: "{{ toJson .StatefulSet.Podz }}", checker: NotNil},
+		{arg: "{{ (toJson .Object).foo }}", checker: IsNil},
+		{arg: "{{ .ConfigMaps.location.Data.path }}", checker: IsNil},
+		{arg: []interface{}{"echo", "{{ .StatefulSet.Name }}"}, checker: IsNil},
+		{arg: map[string]interface{}{"cmd": []string{"{{ .Deployment.Nme }}"}}, checker: NotNil},
+		{arg: "{{ .Options.foo ", checker: NotNil},
+	} {
+		err := CheckTemplates(tc.arg, phases)
+		c.Check(err, tc.checker, Commentf("%#v", tc.arg))
+	}
+}
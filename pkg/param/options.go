@@ -0,0 +1,67 @@
+package param
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// ActionOptions checks the options of an ActionSpec against the options that
+// its BlueprintAction declares. It returns the options with the declared
+// options that are not set set to their default, or to an empty string if
+// they have none. Options that are not declared are passed through, since
+// actions that are created from another ActionSet inherit its options.
+func ActionOptions(specs map[string]crv1alpha1.OptionSpec, opts map[string]string) (map[string]string, error) {
+	if len(specs) == 0 {
+		return opts, nil
+	}
+	names := make([]string, 0, len(specs))
+	for n := range specs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	ao := make(map[string]string, len(opts)+len(specs))
+	for k, v := range opts {
+		ao[k] = v
+	}
+	for _, n := range names {
+		spec := specs[n]
+		v, ok := opts[n]
+		switch {
+		case !ok && spec.Required:
+			return nil, errors.Errorf("Required option %s is not set", n)
+		case !ok:
+			ao[n] = spec.Default
+		default:
+			if err := CheckOptionValue(spec.Type, v); err != nil {
+				return nil, errors.Wrapf(err, "Invalid option %s", n)
+			}
+		}
+	}
+	return ao, nil
+}
+
+// CheckOptionValue returns an error if the value is not of the option type.
+func CheckOptionValue(t crv1alpha1.OptionType, value string) error {
+	var err error
+	switch t {
+	case "", crv1alpha1.OptionTypeString:
+		return nil
+	case crv1alpha1.OptionTypeInt:
+		_, err = strconv.Atoi(value)
+	case crv1alpha1.OptionTypeBool:
+		_, err = strconv.ParseBool(value)
+	case crv1alpha1.OptionTypeDuration:
+		_, err = time.ParseDuration(value)
+	default:
+		return errors.Errorf("Unknown option type %s", t)
+	}
+	if err != nil {
+		return errors.Errorf("Value %q is not of type %s", value, t)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package param
+
+import (
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type OptionsSuite struct{}
+
+var _ = Suite(&OptionsSuite{})
+
+func (s *OptionsSuite) TestActionOptions(c *C) {
+	specs := map[string]crv1alpha1.OptionSpec{
+		"database": {Required: true},
+		"replicas": {Type: crv1alpha1.OptionTypeInt, Default: "1"},
+		"quiesce":  {Type: crv1alpha1.OptionTypeBool},
+		"timeout":  {Type: crv1alpha1.OptionTypeDuration, Default: "10m"},
+	}
+	for _, tc := range []struct {
+		specs   map[string]crv1alpha1.OptionSpec
+		opts    map[string]string
+		out     map[string]string
+		checker Checker
+	}{
+		{
+			specs:   nil,
+			opts:    map[string]string{"foo": "bar"},
+			out:     map[string]string{"foo": "bar"},
+			checker: IsNil,
+		},
+		{
+			specs:   specs,
+			opts:    map[string]string{"database": "mysql"},
+			out:     map[string]string{"database": "mysql", "replicas": "1", "quiesce": "", "timeout": "10m"},
+			checker: IsNil,
+		},
+		{
+			// Options that are not declared are passed through.
+			specs:   specs,
+			opts:    map[string]string{"database": "mysql", "replicas": "3", "quiesce": "true", "timeout": "1h30m", "foo": "bar"},
+			out:     map[string]string{"database": "mysql", "replicas": "3", "quiesce": "true", "timeout": "1h30m", "foo": "bar"},
+			checker: IsNil,
+		},
+		{
+			specs:   specs,
+			opts:    map[string]string{"replicas": "3"},
+			checker: NotNil,
+		},
+		{
+			specs:   specs,
+			opts:    map[string]string{"database": "mysql", "replicas": "three"},
+			checker: NotNil,
+		},
+		{
+			specs:   specs,
+			opts:    map[string]string{"database": "mysql", "quiesce": "maybe"},
+			checker: NotNil,
+		},
+		{
+			specs:   specs,
+			opts:    map[string]string{"database": "mysql", "timeout": "10"},
+			checker: NotNil,
+		},
+	} {
+		out, err := ActionOptions(tc.specs, tc.opts)
+		c.Check(err, tc.checker, Commentf("%#v", tc.opts))
+		if err == nil {
+			c.Check(out, DeepEquals, tc.out)
+		}
+	}
+}
+
+func (s *OptionsSuite) TestCheckOptionValue(c *C) {
+	for _, tc := range []struct {
+		typ     crv1alpha1.OptionType
+		value   string
+		checker Checker
+	}{
+		{typ: "", value: "anything", checker: IsNil},
+		{typ: crv1alpha1.OptionTypeString, value: "", checker: IsNil},
+		{typ: crv1alpha1.OptionTypeInt, value: "-2", checker: IsNil},
+		{typ: crv1alpha1.OptionTypeInt, value: "2.5", checker: NotNil},
+		{typ: crv1alpha1.OptionTypeBool, value: "false", checker: IsNil},
+		{typ: crv1alpha1.OptionTypeBool, value: "no", checker: NotNil},
+		{typ: crv1alpha1.OptionTypeDuration, value: "90s", checker: IsNil},
+		{typ: crv1alpha1.OptionTypeDuration, value: "", checker: NotNil},
+		{typ: "float", value: "1.5", checker: NotNil},
+	} {
+		c.Check(CheckOptionValue(tc.typ, tc.value), tc.checker, Commentf("%s %q", tc.typ, tc.value))
+	}
+}
@@ -3,6 +3,7 @@ package param
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -32,6 +33,8 @@ type TemplateParams struct {
 	Options     map[string]string
 	Object      map[string]interface{}
 	Phases      map[string]*Phase
+	// PodLabels are set on the pods that the functions create.
+	PodLabels map[string]string
 }
 
 // StatefulSetParams are params for stateful sets.
@@ -113,13 +116,17 @@ func New(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interfac
 	if err != nil {
 		return nil, err
 	}
-	prof, err := fetchProfile(ctx, cli, crCli, as.Profile)
+	prof, err := FetchProfile(ctx, cli, crCli, as.Profile)
+	if err != nil {
+		return nil, err
+	}
+	arts, err := fetchArtifacts(ctx, cli, as.Artifacts)
 	if err != nil {
 		return nil, err
 	}
 	now := time.Now().UTC()
 	tp := TemplateParams{
-		ArtifactsIn: as.Artifacts,
+		ArtifactsIn: arts,
 		ConfigMaps:  cms,
 		Secrets:     secrets,
 		Profile:     prof,
@@ -163,7 +170,8 @@ func New(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interfac
 	return &tp, nil
 }
 
-func fetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*Profile, error) {
+// FetchProfile returns the profile that ref refers to with its credential.
+func FetchProfile(ctx context.Context, cli kubernetes.Interface, crCli versioned.Interface, ref *crv1alpha1.ObjectReference) (*Profile, error) {
 	if ref == nil {
 		return nil, errors.New("Cannot execute action without a profile. Specify a profile in the action set")
 	}
@@ -333,22 +341,88 @@ func fetchPVCParams(ctx context.Context, cli kubernetes.Interface, namespace, na
 	}, nil
 }
 
+// SecretValues returns the values of the TemplateParams that must not be
+// logged or stored in the status of an ActionSet: the data of the Secrets of
+// the action and of its phases, the credential of the profile and the
+// sensitive keys of the input artifacts.
+func SecretValues(tp TemplateParams) []string {
+	var values []string
+	for _, s := range tp.Secrets {
+		values = appendSecretData(values, s)
+	}
+	for _, p := range tp.Phases {
+		if p == nil {
+			continue
+		}
+		for _, s := range p.Secrets {
+			values = appendSecretData(values, s)
+		}
+	}
+	if tp.Profile != nil && tp.Profile.Credential.KeyPair != nil {
+		values = append(values, tp.Profile.Credential.KeyPair.ID, tp.Profile.Credential.KeyPair.Secret)
+	}
+	for _, a := range tp.ArtifactsIn {
+		for _, k := range a.Sensitive {
+			if v, ok := a.KeyValue[k]; ok {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func appendSecretData(values []string, s v1.Secret) []string {
+	for _, d := range s.Data {
+		values = append(values, string(d))
+	}
+	for _, d := range s.StringData {
+		values = append(values, d)
+	}
+	return values
+}
+
+// phasesMu synchronizes access to TemplateParams.Phases, which is updated by
+// phases that execute concurrently.
+var phasesMu sync.RWMutex
+
 // UpdatePhaseParams updates the TemplateParams with Phase information
 func UpdatePhaseParams(ctx context.Context, tp *TemplateParams, phaseName string, output map[string]interface{}) {
+	phasesMu.Lock()
+	defer phasesMu.Unlock()
 	tp.Phases[phaseName].Output = output
 }
 
 // InitPhaseParams initializes the TemplateParams with Phase information
 func InitPhaseParams(ctx context.Context, cli kubernetes.Interface, tp *TemplateParams, phaseName string, objects map[string]crv1alpha1.ObjectReference) error {
-	if tp.Phases == nil {
-		tp.Phases = make(map[string]*Phase)
-	}
 	secrets, err := fetchSecrets(ctx, cli, objects)
 	if err != nil {
 		return err
 	}
+	phasesMu.Lock()
+	defer phasesMu.Unlock()
+	if tp.Phases == nil {
+		tp.Phases = make(map[string]*Phase)
+	}
 	tp.Phases[phaseName] = &Phase{
 		Secrets: secrets,
 	}
 	return nil
 }
+
+// PhaseSnapshot returns a copy of the TemplateParams whose Phases are not
+// modified by subsequent calls to InitPhaseParams or UpdatePhaseParams. A
+// phase should render its templates with a snapshot if other phases may be
+// executing concurrently.
+func PhaseSnapshot(tp *TemplateParams) TemplateParams {
+	phasesMu.RLock()
+	defer phasesMu.RUnlock()
+	stp := *tp
+	if tp.Phases != nil {
+		stp.Phases = make(map[string]*Phase, len(tp.Phases))
+		for n, p := range tp.Phases {
+			pc := *p
+			stp.Phases[n] = &pc
+		}
+	}
+	return stp
+}
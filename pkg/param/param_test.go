@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 	"text/template"
@@ -603,3 +604,56 @@ func (s *ParamsSuite) TestRenderingPhaseParams(c *C) {
 		c.Assert(buf.String(), Equals, tc.expected)
 	}
 }
+
+func (s *ParamsSuite) TestPhaseSnapshot(c *C) {
+	ctx := context.Background()
+	cli := fake.NewSimpleClientset()
+	tp := TemplateParams{}
+	err := InitPhaseParams(ctx, cli, &tp, "backup", nil)
+	c.Assert(err, IsNil)
+	UpdatePhaseParams(ctx, &tp, "backup", map[string]interface{}{"replicas": 2})
+
+	stp := PhaseSnapshot(&tp)
+	c.Assert(stp.Phases["backup"].Output, DeepEquals, map[string]interface{}{"replicas": 2})
+
+	// Later updates are not visible in the snapshot.
+	err = InitPhaseParams(ctx, cli, &tp, "restore", nil)
+	c.Assert(err, IsNil)
+	UpdatePhaseParams(ctx, &tp, "backup", map[string]interface{}{"replicas": 3})
+	c.Assert(stp.Phases, HasLen, 1)
+	c.Assert(stp.Phases["backup"].Output, DeepEquals, map[string]interface{}{"replicas": 2})
+	c.Assert(tp.Phases, HasLen, 2)
+}
+
+func (s *ParamsSuite) TestSecretValues(c *C) {
+	tp := TemplateParams{
+		Secrets: map[string]v1.Secret{
+			"db": {Data: map[string][]byte{"password": []byte("db-password")}},
+		},
+		Phases: map[string]*Phase{
+			"backup": {
+				Secrets: map[string]v1.Secret{
+					"restic": {Data: map[string][]byte{"key": []byte("restic-key")}},
+				},
+				Output: map[string]interface{}{"path": "/backup"},
+			},
+		},
+		Profile: &Profile{
+			Credential: Credential{
+				Type:    CredentialTypeKeyPair,
+				KeyPair: &KeyPair{ID: "access-key-id", Secret: "secret-access-key"},
+			},
+		},
+		ArtifactsIn: map[string]crv1alpha1.Artifact{
+			"backup": {
+				KeyValue:  map[string]string{"path": "/backup", "encryptionKey": "encryption-key"},
+				Sensitive: []string{"encryptionKey"},
+			},
+		},
+		Options: map[string]string{"database": "db"},
+	}
+	values := SecretValues(tp)
+	sort.Strings(values)
+	c.Assert(values, DeepEquals, []string{"access-key-id", "db-password", "encryption-key", "restic-key", "secret-access-key"})
+	c.Assert(SecretValues(TemplateParams{}), HasLen, 0)
+}
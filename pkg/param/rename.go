@@ -0,0 +1,202 @@
+package param
+
+import (
+	"reflect"
+	"strings"
+	"text/template/parse"
+)
+
+// RenamePhases returns a copy of arg in which the references of its string
+// templates to the given phases of .Phases, either as fields or as keys
+// passed to index, are replaced by their new names. It recurses through
+// slices, maps and structs like ParseTemplates. Strings without such
+// references are returned unchanged.
+func RenamePhases(arg interface{}, names map[string]string) (interface{}, error) {
+	return mapTemplates(arg, func(s string) (string, error) {
+		return renameStringArg(s, names)
+	})
+}
+
+// mapTemplates returns a copy of arg in which every string is replaced by
+// the result of f, recursing through slices, maps and structs.
+func mapTemplates(arg interface{}, f func(string) (string, error)) (interface{}, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	val, err := mapStrings(reflect.ValueOf(arg), f)
+	if err != nil {
+		return nil, err
+	}
+	return val.Interface(), nil
+}
+
+func mapStrings(val reflect.Value, f func(string) (string, error)) (reflect.Value, error) {
+	switch val.Kind() {
+	case reflect.Interface:
+		if val.IsNil() {
+			return val, nil
+		}
+		rv, err := mapStrings(val.Elem(), f)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		iv := reflect.New(val.Type()).Elem()
+		iv.Set(rv)
+		return iv, nil
+	case reflect.String:
+		s, err := f(val.String())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s).Convert(val.Type()), nil
+	case reflect.Slice:
+		if val.IsNil() {
+			return val, nil
+		}
+		rs := reflect.MakeSlice(val.Type(), val.Len(), val.Len())
+		for i := 0; i < val.Len(); i++ {
+			rv, err := mapStrings(val.Index(i), f)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rs.Index(i).Set(rv)
+		}
+		return rs, nil
+	case reflect.Map:
+		if val.IsNil() {
+			return val, nil
+		}
+		rm := reflect.MakeMapWithSize(val.Type(), val.Len())
+		for _, k := range val.MapKeys() {
+			rk, err := mapStrings(k, f)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rv, err := mapStrings(val.MapIndex(k), f)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rm.SetMapIndex(rk, rv)
+		}
+		return rm, nil
+	case reflect.Struct:
+		rs := reflect.New(val.Type()).Elem()
+		rs.Set(val)
+		for i := 0; i < val.NumField(); i++ {
+			if !rs.Field(i).CanSet() {
+				continue
+			}
+			rv, err := mapStrings(val.Field(i), f)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			rs.Field(i).Set(rv)
+		}
+		return rs, nil
+	}
+	return val, nil
+}
+
+func renameStringArg(arg string, names map[string]string) (string, error) {
+	if !strings.Contains(arg, "{{") {
+		return arg, nil
+	}
+	t, err := parseStringArg(arg)
+	if err != nil {
+		return "", err
+	}
+	if t.Tree == nil || !renameNode(t.Tree.Root, names) {
+		return arg, nil
+	}
+	return t.Tree.Root.String(), nil
+}
+
+// renameNode renames the phases that the node refers to and returns whether
+// it changed.
+func renameNode(node parse.Node, names map[string]string) bool {
+	changed := false
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			changed = renameNode(c, names) || changed
+		}
+	case *parse.ActionNode:
+		changed = renameNode(n.Pipe, names)
+	case *parse.IfNode:
+		changed = renameBranch(&n.BranchNode, names)
+	case *parse.RangeNode:
+		changed = renameBranch(&n.BranchNode, names)
+	case *parse.WithNode:
+		changed = renameBranch(&n.BranchNode, names)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			changed = renameNode(n.Pipe, names)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			changed = renameNode(c, names) || changed
+		}
+	case *parse.CommandNode:
+		for i, a := range n.Args {
+			changed = renameNode(a, names) || changed
+			// index .Phases "name"
+			if i != 2 || !isIdentifier(n.Args[0], "index") || !isPhases(n.Args[1]) {
+				continue
+			}
+			if s, ok := a.(*parse.StringNode); ok {
+				if nn, ok := names[s.Text]; ok {
+					n.Args[i] = &parse.StringNode{NodeType: parse.NodeString, Pos: s.Pos, Quoted: `"` + nn + `"`, Text: nn}
+					changed = true
+				}
+			}
+		}
+	case *parse.ChainNode:
+		changed = renameNode(n.Node, names)
+	case *parse.FieldNode:
+		changed = renameIdent(n.Ident, 0, names)
+	case *parse.VariableNode:
+		changed = renameIdent(n.Ident, 1, names)
+	}
+	return changed
+}
+
+func renameBranch(n *parse.BranchNode, names map[string]string) bool {
+	changed := renameNode(n.Pipe, names)
+	changed = renameNode(n.List, names) || changed
+	return renameNode(n.ElseList, names) || changed
+}
+
+// renameIdent renames the phase in a chain of fields that refers to
+// .Phases.<name> at position i.
+func renameIdent(ident []string, i int, names map[string]string) bool {
+	if len(ident) <= i+1 || ident[i] != "Phases" {
+		return false
+	}
+	nn, ok := names[ident[i+1]]
+	if !ok {
+		return false
+	}
+	ident[i+1] = nn
+	return true
+}
+
+func isIdentifier(node parse.Node, name string) bool {
+	id, ok := node.(*parse.IdentifierNode)
+	return ok && id.Ident == name
+}
+
+func isPhases(node parse.Node) bool {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		return len(n.Ident) == 1 && n.Ident[0] == "Phases"
+	case *parse.VariableNode:
+		return len(n.Ident) == 2 && n.Ident[0] == "$" && n.Ident[1] == "Phases"
+	}
+	return false
+}
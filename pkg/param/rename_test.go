@@ -0,0 +1,79 @@
+package param
+
+import (
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type RenameSuite struct{}
+
+var _ = Suite(&RenameSuite{})
+
+func (s *RenameSuite) TestRenamePhases(c *C) {
+	names := map[string]string{
+		"freeze": "quiesce_freeze",
+		"flush":  "quiesce_flush",
+	}
+	for _, tc := range []struct {
+		arg     interface{}
+		renamed interface{}
+		checker Checker
+	}{
+		{
+			arg:     nil,
+			renamed: nil,
+			checker: IsNil,
+		},
+		{
+			arg:     "{{ .Phases.other.Output.path }}",
+			renamed: "{{ .Phases.other.Output.path }}",
+			checker: IsNil,
+		},
+		{
+			arg:     "{{ .Phases.freeze.Output.path }}/{{ .Object.Name }}",
+			renamed: "{{.Phases.quiesce_freeze.Output.path}}/{{.Object.Name}}",
+			checker: IsNil,
+		},
+		{
+			arg:     `{{ index .Phases "flush" }}`,
+			renamed: `{{index .Phases "quiesce_flush"}}`,
+			checker: IsNil,
+		},
+		{
+			arg:     "{{ if .Phases.freeze }}{{ $.Phases.flush.Output.path | quote }}{{ end }}",
+			renamed: "{{if .Phases.quiesce_freeze}}{{$.Phases.quiesce_flush.Output.path | quote}}{{end}}",
+			checker: IsNil,
+		},
+		{
+			arg: map[string]interface{}{
+				"command": []interface{}{"echo", "{{ .Phases.freeze.Output.path }}"},
+				"count":   3,
+			},
+			renamed: map[string]interface{}{
+				"command": []interface{}{"echo", "{{.Phases.quiesce_freeze.Output.path}}"},
+				"count":   3,
+			},
+			checker: IsNil,
+		},
+		{
+			arg: map[string]crv1alpha1.ObjectReference{
+				"pvc": {Name: "{{ .Phases.flush.Output.pvc }}", Namespace: "default"},
+			},
+			renamed: map[string]crv1alpha1.ObjectReference{
+				"pvc": {Name: "{{.Phases.quiesce_flush.Output.pvc}}", Namespace: "default"},
+			},
+			checker: IsNil,
+		},
+		{
+			arg:     "{{ .Phases.freeze",
+			checker: NotNil,
+		},
+	} {
+		renamed, err := RenamePhases(tc.arg, names)
+		c.Check(err, tc.checker, Commentf("%#v", tc.arg))
+		if err == nil {
+			c.Check(renamed, DeepEquals, tc.renamed)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package param
 
 import (
 	"bytes"
+	"encoding/json"
 	"reflect"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig"
@@ -75,7 +78,9 @@ func render(arg interface{}, tp TemplateParams) (interface{}, error) {
 func RenderArtifacts(arts map[string]crv1alpha1.Artifact, tp TemplateParams) (map[string]crv1alpha1.Artifact, error) {
 	rarts := make(map[string]crv1alpha1.Artifact, len(arts))
 	for name, a := range arts {
-		ra := crv1alpha1.Artifact{}
+		ra := crv1alpha1.Artifact{
+			Sensitive: append([]string(nil), a.Sensitive...),
+		}
 		for k, v := range a.KeyValue {
 			rv, err := renderStringArg(v, tp)
 			if err != nil {
@@ -86,15 +91,101 @@ func RenderArtifacts(arts map[string]crv1alpha1.Artifact, tp TemplateParams) (ma
 			}
 			ra.KeyValue[k] = rv
 		}
+		for k, v := range a.Values {
+			rv, err := renderArtifactValue(v, tp)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to render value %s of artifact %s", k, name)
+			}
+			if ra.Values == nil {
+				ra.Values = make(map[string]interface{}, len(a.Values))
+			}
+			ra.Values[k] = rv
+		}
 		rarts[name] = ra
 	}
 	return rarts, nil
 }
 
-func renderStringArg(arg string, tp TemplateParams) (string, error) {
+// renderArtifactValue renders a structured value of an artifact. A string is
+// rendered as a template and the result is decoded as JSON, so that templates
+// such as {{ toJson .Phases.backup.Output.ids }} produce lists or maps. Other
+// values are not templates and are returned as they are.
+func renderArtifactValue(v interface{}, tp TemplateParams) (interface{}, error) {
+	s, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	rs, err := renderStringArg(s, tp)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeJSONValue(rs)
+}
+
+// DecodeJSONValue decodes a JSON value. Numbers are decoded as json.Number so
+// that large integers, such as IDs, keep their precision.
+func DecodeJSONValue(s string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode %q as JSON", s)
+	}
+	if dec.More() {
+		return nil, errors.Errorf("Failed to decode %q as JSON: unexpected data after the value", s)
+	}
+	return v, nil
+}
+
+// ParseTemplates parses every string template in arg, recursing through
+// slices, maps and structs like the arguments of a phase are rendered. It
+// returns the first template that does not parse.
+func ParseTemplates(arg interface{}) error {
+	if arg == nil {
+		return nil
+	}
+	val := reflect.ValueOf(arg)
+	switch val.Kind() {
+	case reflect.String:
+		_, err := parseStringArg(val.String())
+		return err
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			if err := ParseTemplates(val.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, k := range val.MapKeys() {
+			if err := ParseTemplates(k.Interface()); err != nil {
+				return err
+			}
+			if err := ParseTemplates(val.MapIndex(k).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < val.NumField(); i++ {
+			if err := ParseTemplates(val.Field(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseStringArg(arg string) (*template.Template, error) {
 	t, err := template.New("config").Option("missingkey=error").Funcs(sprig.TxtFuncMap()).Parse(arg)
 	if err != nil {
-		return "", errors.WithStack(err)
+		return nil, errors.Wrapf(err, "Failed to parse template {%s}", arg)
+	}
+	return t, nil
+}
+
+func renderStringArg(arg string, tp TemplateParams) (string, error) {
+	t, err := parseStringArg(arg)
+	if err != nil {
+		return "", err
 	}
 	buf := bytes.NewBuffer(nil)
 	if err = t.Execute(buf, tp); err != nil {
@@ -103,6 +194,23 @@ func renderStringArg(arg string, tp TemplateParams) (string, error) {
 	return buf.String(), nil
 }
 
+// RenderCondition renders the condition template and parses the result as a
+// boolean. An empty condition is true.
+func RenderCondition(cond string, tp TemplateParams) (bool, error) {
+	if cond == "" {
+		return true, nil
+	}
+	rc, err := renderStringArg(cond, tp)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(rc))
+	if err != nil {
+		return false, errors.Wrapf(err, "Condition {%s} did not render to a boolean", cond)
+	}
+	return b, nil
+}
+
 // RenderObjectRefs function renders object refs from TemplateParams
 func RenderObjectRefs(in map[string]crv1alpha1.ObjectReference, tp TemplateParams) (map[string]crv1alpha1.ObjectReference, error) {
 	out := make(map[string]crv1alpha1.ObjectReference, len(in))
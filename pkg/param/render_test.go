@@ -1,6 +1,8 @@
 package param
 
 import (
+	"encoding/json"
+
 	. "gopkg.in/check.v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
@@ -136,3 +138,114 @@ func (s *RenderSuite) TestRenderObjects(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(out["authSecret"].Name, Equals, "secret-name")
 }
+
+func (s *RenderSuite) TestRenderCondition(c *C) {
+	tp := TemplateParams{
+		Options: map[string]string{
+			"quiesce": "true",
+			"other":   "notABool",
+		},
+		StatefulSet: &StatefulSetParams{
+			PersistentVolumeClaims: map[string]map[string]string{},
+		},
+	}
+	for _, tc := range []struct {
+		cond    string
+		out     bool
+		checker Checker
+	}{
+		{
+			cond:    "",
+			out:     true,
+			checker: IsNil,
+		},
+		{
+			cond:    "false",
+			out:     false,
+			checker: IsNil,
+		},
+		{
+			cond:    `{{ eq .Options.quiesce "true" }}`,
+			out:     true,
+			checker: IsNil,
+		},
+		{
+			cond:    " {{ .Options.quiesce }}\n",
+			out:     true,
+			checker: IsNil,
+		},
+		{
+			cond:    "{{ gt (len .StatefulSet.PersistentVolumeClaims) 0 }}",
+			out:     false,
+			checker: IsNil,
+		},
+		{
+			cond:    "{{ .Options.other }}",
+			out:     false,
+			checker: NotNil,
+		},
+		{
+			cond:    "{{ .Options.missing }}",
+			out:     false,
+			checker: NotNil,
+		},
+	} {
+		out, err := RenderCondition(tc.cond, tp)
+		c.Check(err, tc.checker, Commentf("%s", tc.cond))
+		c.Check(out, Equals, tc.out, Commentf("%s", tc.cond))
+	}
+}
+
+func (s *RenderSuite) TestRenderArtifactValues(c *C) {
+	tp := TemplateParams{
+		Phases: map[string]*Phase{
+			"backup": {
+				Output: map[string]interface{}{
+					"ids":  []interface{}{"a1", "b2"},
+					"path": "/backups/db",
+				},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		values  map[string]interface{}
+		out     map[string]interface{}
+		checker Checker
+	}{
+		{
+			values: map[string]interface{}{
+				"ids":   "{{ toJson .Phases.backup.Output.ids }}",
+				"path":  "{{ toJson .Phases.backup.Output.path }}",
+				"count": "{{ len .Phases.backup.Output.ids }}",
+				"fixed": []interface{}{"c3"},
+			},
+			out: map[string]interface{}{
+				"ids":   []interface{}{"a1", "b2"},
+				"path":  "/backups/db",
+				"count": json.Number("2"),
+				"fixed": []interface{}{"c3"},
+			},
+			checker: IsNil,
+		},
+		{
+			// Values must render to JSON.
+			values:  map[string]interface{}{"path": "{{ .Phases.backup.Output.path }}"},
+			checker: NotNil,
+		},
+		{
+			values:  map[string]interface{}{"ids": "{{ .Phases.missing.Output.ids }}"},
+			checker: NotNil,
+		},
+	} {
+		arts, err := RenderArtifacts(map[string]crv1alpha1.Artifact{"backup": {Values: tc.values}}, tp)
+		c.Assert(err, tc.checker, Commentf("%#v", tc.values))
+		if err != nil {
+			continue
+		}
+		c.Assert(arts["backup"].Values, DeepEquals, tc.out)
+		// Values can be used in templates of later actions.
+		id, err := renderStringArg("{{ index .ArtifactsIn.backup.Values.ids 1 }}", TemplateParams{ArtifactsIn: arts})
+		c.Assert(err, IsNil)
+		c.Assert(id, Equals, "b2")
+	}
+}
@@ -0,0 +1,113 @@
+package param
+
+import (
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// UnresolvedOutputs returns a copy of arg in which the references of its
+// string templates to the output of phases, either as fields of
+// .Phases.<name>.Output or as keys passed to index, are replaced by
+// placeholders. A placeholder renders as the reference enclosed in angle
+// brackets, such as <.Phases.backup.Output.path>. It is used to render phases
+// that are not executed. It recurses through slices, maps and structs like
+// RenamePhases.
+func UnresolvedOutputs(arg interface{}) (interface{}, error) {
+	return mapTemplates(arg, unresolveStringArg)
+}
+
+func unresolveStringArg(arg string) (string, error) {
+	if !strings.Contains(arg, "{{") {
+		return arg, nil
+	}
+	t, err := parseStringArg(arg)
+	if err != nil {
+		return "", err
+	}
+	if t.Tree == nil || !unresolveNode(t.Tree.Root) {
+		return arg, nil
+	}
+	return t.Tree.Root.String(), nil
+}
+
+// unresolveNode replaces the references to the output of phases within the
+// node by placeholders and returns whether it changed.
+func unresolveNode(node parse.Node) bool {
+	changed := false
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			changed = unresolveNode(c) || changed
+		}
+	case *parse.ActionNode:
+		changed = unresolveNode(n.Pipe)
+	case *parse.IfNode:
+		changed = unresolveBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		changed = unresolveBranch(&n.BranchNode)
+	case *parse.WithNode:
+		changed = unresolveBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			changed = unresolveNode(n.Pipe)
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			changed = unresolveNode(c) || changed
+		}
+	case *parse.CommandNode:
+		// index .Phases.<name>.Output "key"
+		if len(n.Args) >= 2 && isIdentifier(n.Args[0], "index") {
+			if _, ok := outputPhase(n.Args[1]); ok {
+				n.Args = []parse.Node{placeholder(n)}
+				return true
+			}
+		}
+		for i, a := range n.Args {
+			if isOutputRef(a) {
+				n.Args[i] = placeholder(a)
+				changed = true
+				continue
+			}
+			changed = unresolveNode(a) || changed
+		}
+	case *parse.ChainNode:
+		changed = unresolveNode(n.Node)
+	}
+	return changed
+}
+
+func unresolveBranch(n *parse.BranchNode) bool {
+	changed := unresolveNode(n.Pipe)
+	changed = unresolveNode(n.List) || changed
+	return unresolveNode(n.ElseList) || changed
+}
+
+// isOutputRef returns whether the node is a chain of fields that refers to
+// .Phases.<name>.Output.
+func isOutputRef(node parse.Node) bool {
+	var ident []string
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		ident = n.Ident
+	case *parse.VariableNode:
+		if len(n.Ident) == 0 || n.Ident[0] != "$" {
+			return false
+		}
+		ident = n.Ident[1:]
+	}
+	return len(ident) >= 3 && ident[0] == "Phases" && ident[2] == "Output"
+}
+
+// placeholder returns the string that the reference renders as.
+func placeholder(node parse.Node) *parse.StringNode {
+	text := "<" + node.String() + ">"
+	return &parse.StringNode{NodeType: parse.NodeString, Pos: node.Position(), Quoted: strconv.Quote(text), Text: text}
+}
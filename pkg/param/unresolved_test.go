@@ -0,0 +1,68 @@
+package param
+
+import (
+	. "gopkg.in/check.v1"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+type UnresolvedSuite struct{}
+
+var _ = Suite(&UnresolvedSuite{})
+
+func (s *UnresolvedSuite) TestUnresolvedOutputs(c *C) {
+	tp := TemplateParams{
+		Options: map[string]string{"bucket": "backups"},
+		Phases: map[string]*Phase{
+			"dump": &Phase{},
+		},
+	}
+	// The output of the phase is not known.
+	_, err := renderStringArg("{{ .Phases.dump.Output.path }}", tp)
+	c.Assert(err, NotNil)
+	for _, tc := range []struct {
+		arg      interface{}
+		rendered interface{}
+	}{
+		{
+			arg:      "{{ .Options.bucket }}",
+			rendered: "backups",
+		},
+		{
+			arg:      "{{ .Options.bucket }}/{{ .Phases.dump.Output.path }}",
+			rendered: "backups/<.Phases.dump.Output.path>",
+		},
+		{
+			arg:      `{{ index .Phases.dump.Output "path" }}`,
+			rendered: `<index .Phases.dump.Output "path">`,
+		},
+		{
+			arg:      `{{ printf "%s/%s" .Options.bucket $.Phases.dump.Output.path }}`,
+			rendered: "backups/<$.Phases.dump.Output.path>",
+		},
+		{
+			arg:      "{{ if .Phases.dump.Output.path }}{{ .Phases.dump.Output.path | upper }}{{ end }}",
+			rendered: "<.PHASES.DUMP.OUTPUT.PATH>",
+		},
+		{
+			arg: map[string]interface{}{
+				"command": []interface{}{"restore", "{{ .Phases.dump.Output.path }}"},
+				"count":   3,
+			},
+			rendered: map[interface{}]interface{}{
+				"command": []interface{}{"restore", "<.Phases.dump.Output.path>"},
+				"count":   3,
+			},
+		},
+		{
+			arg:      crv1alpha1.ObjectReference{Name: "{{ .Phases.dump.Output.secret }}"},
+			rendered: crv1alpha1.ObjectReference{Name: "<.Phases.dump.Output.secret>"},
+		},
+	} {
+		arg, err := UnresolvedOutputs(tc.arg)
+		c.Assert(err, IsNil)
+		rendered, err := render(arg, tp)
+		c.Assert(err, IsNil)
+		c.Check(rendered, DeepEquals, tc.rendered)
+	}
+}
@@ -2,11 +2,13 @@ package kanister
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/poll"
 )
 
 // Phase is an atomic unit of execution.
@@ -15,6 +17,10 @@ type Phase struct {
 	args    map[string]interface{}
 	objects map[string]crv1alpha1.ObjectReference
 	f       Func
+	deps    []int
+	cond    string
+	retry   *retryPolicy
+	timeout time.Duration
 }
 
 // Name returns the name of this phase.
@@ -22,11 +28,35 @@ func (p *Phase) Name() string {
 	return p.name
 }
 
+// FuncName returns the name of the function that this phase executes.
+func (p *Phase) FuncName() string {
+	if p.f == nil {
+		return ""
+	}
+	return p.f.Name()
+}
+
+// Timeout returns the maximum duration of this phase, including retries. A
+// zero value means the phase has no timeout.
+func (p *Phase) Timeout() time.Duration {
+	return p.timeout
+}
+
 // Objects returns the phase object references
 func (p *Phase) Objects() map[string]crv1alpha1.ObjectReference {
 	return p.objects
 }
 
+// Skip renders the phase's condition and returns true if it is false, in
+// which case the phase should not be executed.
+func (p *Phase) Skip(tp param.TemplateParams) (bool, error) {
+	ok, err := param.RenderCondition(p.cond, tp)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to evaluate condition of phase %s", p.name)
+	}
+	return !ok, nil
+}
+
 // Exec renders the argument templates in this Phase's Func and executes with
 // those arguments.
 func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) (map[string]interface{}, error) {
@@ -36,8 +66,12 @@ func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string
 		if !ok {
 			return nil, errors.Errorf("Action {%s} not found in action map", action)
 		}
+		phases := a.Phases
+		if a.DeferPhase != nil {
+			phases = append(phases[:len(phases):len(phases)], *a.DeferPhase)
+		}
 		// Render the argument templates for the Phase's function
-		for _, ap := range a.Phases {
+		for _, ap := range phases {
 			if ap.Name != p.name {
 				continue
 			}
@@ -55,12 +89,43 @@ func (p *Phase) Exec(ctx context.Context, bp crv1alpha1.Blueprint, action string
 	return p.f.Exec(ctx, tp, p.args)
 }
 
+// ExecWithRetries calls Exec until it succeeds, fails with an error that is
+// not retryable, or the phase's retry policy runs out of attempts. The
+// onAttempt callback, if not nil, is invoked with the attempt number before
+// every execution.
+func (p *Phase) ExecWithRetries(ctx context.Context, bp crv1alpha1.Blueprint, action string, tp param.TemplateParams, onAttempt func(int)) (map[string]interface{}, error) {
+	rp := p.retry
+	if rp == nil {
+		rp = &retryPolicy{maxAttempts: 1}
+	}
+	isRetryable := func(err error) bool {
+		return ctx.Err() == nil && rp.isRetryable(err)
+	}
+	var output map[string]interface{}
+	attempt := 0
+	err := poll.WaitWithBackoffWithRetries(ctx, rp.backoff, rp.maxAttempts-1, isRetryable, func(ctx context.Context) (bool, error) {
+		attempt++
+		if onAttempt != nil {
+			onAttempt(attempt)
+		}
+		var err error
+		output, err = p.Exec(ctx, bp, action, tp)
+		return err == nil, err
+	})
+	return output, err
+}
+
 // GetPhases renders the returns a list of Phases with pre-rendered arguments.
+// If the action uses other actions, the Blueprint must have been expanded
+// with ExpandBlueprint.
 func GetPhases(bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) ([]*Phase, error) {
 	a, ok := bp.Actions[action]
 	if !ok {
 		return nil, errors.Errorf("Action {%s} not found in action map", action)
 	}
+	if err := checkExpanded(action, *a); err != nil {
+		return nil, err
+	}
 	funcMu.RLock()
 	defer funcMu.RUnlock()
 	// We first check that all requested phases are registered.
@@ -69,21 +134,67 @@ func GetPhases(bp crv1alpha1.Blueprint, action string, tp param.TemplateParams)
 			return nil, errors.Errorf("Requested function {%s} has not been registered", p.Func)
 		}
 	}
+	deps, err := phaseDependencies(a.Phases)
+	if err != nil {
+		return nil, err
+	}
 	phases := make([]*Phase, 0, len(a.Phases))
-	for _, p := range a.Phases {
-		objs, err := param.RenderObjectRefs(p.ObjectRefs, tp)
+	for i, p := range a.Phases {
+		phase, err := newPhase(p, tp)
 		if err != nil {
 			return nil, err
 		}
-		phases = append(phases, &Phase{
-			name:    p.Name,
-			objects: objs,
-			f:       funcs[p.Func],
-		})
+		phase.deps = deps[i]
+		phases = append(phases, phase)
 	}
 	return phases, nil
 }
 
+// GetDeferPhase returns the action's DeferPhase with pre-rendered object
+// references, or nil if the action does not have one.
+func GetDeferPhase(bp crv1alpha1.Blueprint, action string, tp param.TemplateParams) (*Phase, error) {
+	a, ok := bp.Actions[action]
+	if !ok {
+		return nil, errors.Errorf("Action {%s} not found in action map", action)
+	}
+	if err := checkExpanded(action, *a); err != nil {
+		return nil, err
+	}
+	if a.DeferPhase == nil {
+		return nil, nil
+	}
+	funcMu.RLock()
+	defer funcMu.RUnlock()
+	if _, ok := funcs[a.DeferPhase.Func]; !ok {
+		return nil, errors.Errorf("Requested function {%s} has not been registered", a.DeferPhase.Func)
+	}
+	return newPhase(*a.DeferPhase, tp)
+}
+
+// newPhase must be called with funcMu held.
+func newPhase(p crv1alpha1.BlueprintPhase, tp param.TemplateParams) (*Phase, error) {
+	objs, err := param.RenderObjectRefs(p.ObjectRefs, tp)
+	if err != nil {
+		return nil, err
+	}
+	rp, err := newRetryPolicy(p.RetryPolicy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid retry policy for phase %s", p.Name)
+	}
+	var timeout time.Duration
+	if p.Timeout != nil {
+		timeout = p.Timeout.Duration
+	}
+	return &Phase{
+		name:    p.Name,
+		objects: objs,
+		f:       funcs[p.Func],
+		cond:    p.If,
+		retry:   rp,
+		timeout: timeout,
+	}, nil
+}
+
 func checkRequiredArgs(reqArgs []string, args map[string]interface{}) error {
 	for _, a := range reqArgs {
 		if _, ok := args[a]; !ok {
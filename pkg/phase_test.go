@@ -2,8 +2,11 @@ package kanister
 
 import (
 	"context"
+	"time"
 
+	"github.com/pkg/errors"
 	. "gopkg.in/check.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/param"
@@ -14,6 +17,8 @@ type PhaseSuite struct{}
 var (
 	_      = Suite(&PhaseSuite{})
 	_ Func = (*testFunc)(nil)
+	_ Func = (*flakyFunc)(nil)
+	_ Func = (*deferFunc)(nil)
 )
 
 type testFunc struct {
@@ -69,3 +74,209 @@ func (s *PhaseSuite) TestExec(c *C) {
 		c.Assert(output, Equals, tc.expected)
 	}
 }
+
+type flakyFunc struct {
+	calls    int
+	failures int
+	err      error
+}
+
+func (*flakyFunc) Name() string {
+	return "flaky"
+}
+
+func (ff *flakyFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	ff.calls++
+	if ff.calls <= ff.failures {
+		return nil, ff.err
+	}
+	return map[string]interface{}{"calls": ff.calls}, nil
+}
+
+func (ff *flakyFunc) RequiredArgs() []string {
+	return nil
+}
+
+func (s *PhaseSuite) TestExecWithRetries(c *C) {
+	backoff := &metav1.Duration{Duration: time.Millisecond}
+	for _, tc := range []struct {
+		policy   *crv1alpha1.RetryPolicy
+		failures int
+		err      error
+		calls    int
+		checker  Checker
+	}{
+		{
+			policy:   nil,
+			failures: 1,
+			err:      errors.New("transient"),
+			calls:    1,
+			checker:  NotNil,
+		},
+		{
+			policy:   &crv1alpha1.RetryPolicy{MaxAttempts: 3, MinBackoff: backoff, MaxBackoff: backoff},
+			failures: 2,
+			err:      errors.New("transient"),
+			calls:    3,
+			checker:  IsNil,
+		},
+		{
+			policy:   &crv1alpha1.RetryPolicy{MaxAttempts: 2, MinBackoff: backoff, MaxBackoff: backoff},
+			failures: 2,
+			err:      errors.New("transient"),
+			calls:    2,
+			checker:  NotNil,
+		},
+		{
+			policy:   &crv1alpha1.RetryPolicy{MaxAttempts: 3, MinBackoff: backoff, MaxBackoff: backoff, RetryableErrors: []string{"^transient"}},
+			failures: 2,
+			err:      errors.New("permanent"),
+			calls:    1,
+			checker:  NotNil,
+		},
+		{
+			policy:   &crv1alpha1.RetryPolicy{MaxAttempts: 3, MinBackoff: backoff, MaxBackoff: backoff, RetryableErrors: []string{"^transient"}},
+			failures: 1,
+			err:      errors.New("transient error"),
+			calls:    2,
+			checker:  IsNil,
+		},
+	} {
+		rp, err := newRetryPolicy(tc.policy)
+		c.Assert(err, IsNil)
+		ff := &flakyFunc{failures: tc.failures, err: tc.err}
+		p := Phase{args: map[string]interface{}{}, f: ff, retry: rp}
+		var attempts []int
+		_, err = p.ExecWithRetries(context.Background(), crv1alpha1.Blueprint{}, "", param.TemplateParams{}, func(a int) {
+			attempts = append(attempts, a)
+		})
+		c.Check(err, tc.checker)
+		c.Check(ff.calls, Equals, tc.calls)
+		c.Check(attempts, HasLen, tc.calls)
+	}
+}
+
+func (s *PhaseSuite) TestInvalidRetryPolicy(c *C) {
+	for _, rp := range []*crv1alpha1.RetryPolicy{
+		{MaxAttempts: -1},
+		{MaxAttempts: 2, RetryableErrors: []string{"("}},
+	} {
+		_, err := newRetryPolicy(rp)
+		c.Check(err, NotNil)
+	}
+}
+
+type deferFunc struct {
+	output string
+}
+
+func (*deferFunc) Name() string {
+	return "deferMock"
+}
+
+func (df *deferFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	df.output = args["testKey"].(string)
+	return nil, nil
+}
+
+func (df *deferFunc) RequiredArgs() []string {
+	return []string{"testKey"}
+}
+
+func (s *PhaseSuite) TestGetDeferPhase(c *C) {
+	df := &deferFunc{}
+	err := Register(df)
+	c.Assert(err, IsNil)
+
+	tp := param.TemplateParams{
+		Options: map[string]string{
+			"test": "hello",
+		},
+	}
+	bp := crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"noDefer": &crv1alpha1.BlueprintAction{},
+			"unregistered": &crv1alpha1.BlueprintAction{
+				DeferPhase: &crv1alpha1.BlueprintPhase{
+					Name: "cleanup",
+					Func: "notRegistered",
+				},
+			},
+			"defer": &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "backup",
+						Func: "deferMock",
+						Args: map[string]interface{}{
+							"testKey": "backup",
+						},
+					},
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{
+					Name: "cleanup",
+					Func: "deferMock",
+					Args: map[string]interface{}{
+						"testKey": "{{ .Options.test }} cleanup",
+					},
+				},
+			},
+		},
+	}
+
+	p, err := GetDeferPhase(bp, "noDefer", tp)
+	c.Assert(err, IsNil)
+	c.Assert(p, IsNil)
+
+	_, err = GetDeferPhase(bp, "unregistered", tp)
+	c.Assert(err, NotNil)
+
+	_, err = GetDeferPhase(bp, "missing", tp)
+	c.Assert(err, NotNil)
+
+	p, err = GetDeferPhase(bp, "defer", tp)
+	c.Assert(err, IsNil)
+	c.Assert(p, NotNil)
+	c.Assert(p.Name(), Equals, "cleanup")
+	_, err = p.Exec(context.Background(), bp, "defer", tp)
+	c.Assert(err, IsNil)
+	c.Assert(df.output, Equals, "hello cleanup")
+}
+
+func (s *PhaseSuite) TestSkip(c *C) {
+	tp := param.TemplateParams{
+		Options: map[string]string{
+			"quiesce": "false",
+		},
+	}
+	for _, tc := range []struct {
+		cond    string
+		skip    bool
+		checker Checker
+	}{
+		{
+			cond:    "",
+			skip:    false,
+			checker: IsNil,
+		},
+		{
+			cond:    `{{ eq .Options.quiesce "true" }}`,
+			skip:    true,
+			checker: IsNil,
+		},
+		{
+			cond:    "{{ .Options.quiesce }}",
+			skip:    true,
+			checker: IsNil,
+		},
+		{
+			cond:    "{{ .Options.missing }}",
+			skip:    false,
+			checker: NotNil,
+		},
+	} {
+		p := Phase{name: "myPhase", cond: tc.cond}
+		skip, err := p.Skip(tp)
+		c.Check(err, tc.checker)
+		c.Check(skip, Equals, tc.skip)
+	}
+}
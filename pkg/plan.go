@@ -0,0 +1,137 @@
+package kanister
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+// PhasePlan is a phase of an action rendered as it would be executed.
+type PhasePlan struct {
+	Name    string
+	Func    string
+	Args    map[string]interface{}
+	Objects map[string]crv1alpha1.ObjectReference
+	// Skip is true if the condition of the phase is false.
+	Skip bool
+	// Err is set if the phase could not be rendered.
+	Err error
+}
+
+// PlanPhases renders the object references, the condition and the arguments
+// of the phases of the action and of its deferred phase without executing
+// them. A phase that fails to render does not prevent the others from being
+// rendered. Since no phase is executed, the references to the output of
+// phases are rendered as placeholders, such as <.Phases.backup.Output.path>.
+// The Secrets of the phases' objects are added to tp.
+func PlanPhases(ctx context.Context, cli kubernetes.Interface, bp crv1alpha1.Blueprint, action string, tp *param.TemplateParams) ([]PhasePlan, *PhasePlan, error) {
+	a, ok := bp.Actions[action]
+	if !ok {
+		return nil, nil, errors.Errorf("Action {%s} not found in action map", action)
+	}
+	if err := checkExpanded(action, *a); err != nil {
+		return nil, nil, err
+	}
+	plans := make([]PhasePlan, 0, len(a.Phases))
+	for _, p := range a.Phases {
+		plans = append(plans, planPhase(ctx, cli, p, tp))
+	}
+	if a.DeferPhase == nil {
+		return plans, nil, nil
+	}
+	deferPlan := planPhase(ctx, cli, *a.DeferPhase, tp)
+	return plans, &deferPlan, nil
+}
+
+func planPhase(ctx context.Context, cli kubernetes.Interface, p crv1alpha1.BlueprintPhase, tp *param.TemplateParams) PhasePlan {
+	plan := PhasePlan{
+		Name: p.Name,
+		Func: p.Func,
+	}
+	funcMu.RLock()
+	f, ok := funcs[p.Func]
+	funcMu.RUnlock()
+	if !ok {
+		plan.Err = errors.Errorf("Requested function {%s} has not been registered", p.Func)
+		return plan
+	}
+	p, err := unresolvedOutputs(p)
+	if err != nil {
+		plan.Err = err
+		return plan
+	}
+	if plan.Objects, plan.Err = param.RenderObjectRefs(p.ObjectRefs, *tp); plan.Err != nil {
+		return plan
+	}
+	if plan.Err = param.InitPhaseParams(ctx, cli, tp, p.Name, plan.Objects); plan.Err != nil {
+		return plan
+	}
+	ok, err = param.RenderCondition(p.If, *tp)
+	if err != nil {
+		plan.Err = errors.Wrapf(err, "Failed to evaluate condition of phase %s", p.Name)
+		return plan
+	}
+	plan.Skip = !ok
+	args, err := param.RenderArgs(p.Args, *tp)
+	if err != nil {
+		plan.Err = err
+		return plan
+	}
+	plan.Args = jsonValue(args).(map[string]interface{})
+	if err := checkRequiredArgs(f.RequiredArgs(), plan.Args); err != nil {
+		plan.Err = errors.Wrapf(err, "Required args missing for function %s", f.Name())
+	}
+	return plan
+}
+
+// unresolvedOutputs returns a copy of the phase whose object references,
+// condition and arguments refer to the output of phases with placeholders.
+func unresolvedOutputs(p crv1alpha1.BlueprintPhase) (crv1alpha1.BlueprintPhase, error) {
+	objs, err := param.UnresolvedOutputs(p.ObjectRefs)
+	if err != nil {
+		return p, err
+	}
+	p.ObjectRefs, _ = objs.(map[string]crv1alpha1.ObjectReference)
+	cond, err := param.UnresolvedOutputs(p.If)
+	if err != nil {
+		return p, err
+	}
+	p.If = cond.(string)
+	args, err := param.UnresolvedOutputs(p.Args)
+	if err != nil {
+		return p, err
+	}
+	p.Args, _ = args.(map[string]interface{})
+	return p, nil
+}
+
+// jsonValue converts the maps of rendered arguments, whose keys are
+// interfaces, to maps with string keys so that they can be encoded as JSON.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = jsonValue(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = jsonValue(val)
+		}
+		return s
+	}
+	return v
+}
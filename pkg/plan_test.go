@@ -0,0 +1,138 @@
+package kanister
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+)
+
+type PlanSuite struct{}
+
+var (
+	_      = Suite(&PlanSuite{})
+	_ Func = (*planFunc)(nil)
+)
+
+type planFunc struct{}
+
+func (*planFunc) Name() string {
+	return "planMock"
+}
+
+func (*planFunc) Exec(ctx context.Context, tp param.TemplateParams, args map[string]interface{}) (map[string]interface{}, error) {
+	panic("planned phases must not be executed")
+}
+
+func (*planFunc) RequiredArgs() []string {
+	return []string{"key"}
+}
+
+func (s *PlanSuite) TestPlanPhases(c *C) {
+	err := Register(&planFunc{})
+	c.Assert(err, IsNil)
+
+	tp := &param.TemplateParams{
+		Options: map[string]string{
+			"test":    "hello",
+			"quiesce": "false",
+		},
+	}
+	bp := crv1alpha1.Blueprint{
+		Actions: map[string]*crv1alpha1.BlueprintAction{
+			"backup": &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "rendered",
+						Func: "planMock",
+						Args: map[string]interface{}{
+							"key": "{{ .Options.test }} world",
+							"nested": map[string]interface{}{
+								"list": []interface{}{"{{ .Options.test }}"},
+							},
+						},
+					},
+					{
+						Name: "skipped",
+						Func: "planMock",
+						If:   `{{ eq .Options.quiesce "true" }}`,
+						Args: map[string]interface{}{
+							"key": "value",
+						},
+					},
+					{
+						Name: "output",
+						Func: "planMock",
+						Args: map[string]interface{}{
+							"key": `{{ .Phases.rendered.Output.path }}/{{ index .Phases.rendered.Output "id" }}`,
+						},
+					},
+					{
+						Name: "missingOption",
+						Func: "planMock",
+						Args: map[string]interface{}{
+							"key": "{{ .Options.missing }}",
+						},
+					},
+					{
+						Name: "missingArg",
+						Func: "planMock",
+					},
+					{
+						Name: "unregistered",
+						Func: "notRegistered",
+					},
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{
+					Name: "cleanup",
+					Func: "planMock",
+					Args: map[string]interface{}{
+						"key": "{{ .Options.test }} cleanup",
+					},
+				},
+			},
+		},
+	}
+
+	_, _, err = PlanPhases(context.Background(), fake.NewSimpleClientset(), bp, "missing", tp)
+	c.Assert(err, NotNil)
+
+	plans, deferPlan, err := PlanPhases(context.Background(), fake.NewSimpleClientset(), bp, "backup", tp)
+	c.Assert(err, IsNil)
+	c.Assert(plans, HasLen, 6)
+
+	c.Check(plans[0].Name, Equals, "rendered")
+	c.Check(plans[0].Func, Equals, "planMock")
+	c.Check(plans[0].Err, IsNil)
+	c.Check(plans[0].Skip, Equals, false)
+	c.Check(plans[0].Args, DeepEquals, map[string]interface{}{
+		"key": "hello world",
+		"nested": map[string]interface{}{
+			"list": []interface{}{"hello"},
+		},
+	})
+
+	c.Check(plans[1].Err, IsNil)
+	c.Check(plans[1].Skip, Equals, true)
+	c.Check(plans[1].Args, DeepEquals, map[string]interface{}{"key": "value"})
+
+	// The output of phases is not known since they are not executed.
+	c.Check(plans[2].Err, IsNil)
+	c.Check(plans[2].Args, DeepEquals, map[string]interface{}{
+		"key": `<.Phases.rendered.Output.path>/<index .Phases.rendered.Output "id">`,
+	})
+
+	// A phase that fails to render does not prevent the others from being
+	// rendered.
+	for _, p := range plans[3:] {
+		c.Check(p.Err, NotNil, Commentf("Phase %s", p.Name))
+	}
+
+	c.Assert(deferPlan, NotNil)
+	c.Check(deferPlan.Name, Equals, "cleanup")
+	c.Check(deferPlan.Err, IsNil)
+	c.Check(deferPlan.Args, DeepEquals, map[string]interface{}{"key": "hello cleanup"})
+}
@@ -43,17 +43,9 @@ func WaitWithBackoff(ctx context.Context, b backoff.Backoff, f Func) error {
 		if ok, err := f(ctx); err != nil || ok {
 			return err
 		}
-		select {
-		case <-ctx.Done():
+		if !sleep(ctx, b.Duration()) {
 			return errors.WithStack(ctx.Err())
-		default:
 		}
-		sleep := b.Duration()
-		if deadline, ok := ctx.Deadline(); ok {
-			ctxSleep := deadline.Sub(time.Now())
-			sleep = minDuration(sleep, ctxSleep)
-		}
-		time.Sleep(sleep)
 	}
 }
 
@@ -85,23 +77,24 @@ func WaitWithBackoffWithRetries(ctx context.Context, b backoff.Backoff, numRetri
 		} else if ok {
 			return nil
 		}
-		select {
-		case <-ctx.Done():
+		if !sleep(ctx, b.Duration()) {
 			return errors.Wrap(ctx.Err(), "Context done while polling")
-		default:
-		}
-		sleep := b.Duration()
-		if deadline, ok := ctx.Deadline(); ok {
-			ctxSleep := deadline.Sub(time.Now())
-			sleep = minDuration(sleep, ctxSleep)
 		}
-		time.Sleep(sleep)
 	}
 }
 
-func minDuration(a, b time.Duration) time.Duration {
-	if a < b {
-		return a
+// sleep waits for the duration d. It returns false if the context is done
+// before, or was already done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
 	}
-	return b
 }
@@ -137,3 +137,22 @@ func (s *PollSuite) TestWaitWithBackoffBackoff(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(time.Now().Sub(now) > (numIterations-1)*time.Millisecond, Equals, true)
 }
+
+func (s *PollSuite) TestWaitWithBackoffWithRetriesCancelledDuringBackoff(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := func(context.Context) (bool, error) {
+		// Cancel while waiting for the next attempt.
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return false, errFake
+	}
+	b := backoff.Backoff{
+		Min: time.Hour,
+		Max: time.Hour,
+	}
+
+	now := time.Now()
+	err := WaitWithBackoffWithRetries(ctx, b, 5, IsAlwaysRetryable, f)
+	c.Assert(err, NotNil)
+	c.Assert(time.Since(now) < time.Minute, Equals, true)
+}
@@ -0,0 +1,168 @@
+// Package redact removes secret values, such as credentials and encryption
+// keys, from logs, events and the status of ActionSets.
+package redact
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// Mask replaces the secret values.
+	Mask = "***"
+	// minLength is the length of the shortest value that is redacted.
+	// Shorter values, such as ports or flags, would redact unrelated text.
+	minLength = 4
+)
+
+// Redactor replaces secret values with Mask.
+type Redactor struct {
+	values []string
+	re     *strings.Replacer
+}
+
+// New returns a Redactor of the values. Empty values and values shorter than
+// four characters are ignored.
+func New(values ...string) *Redactor {
+	seen := make(map[string]bool, len(values))
+	var vs []string
+	for _, v := range values {
+		if len(v) < minLength || seen[v] {
+			continue
+		}
+		seen[v] = true
+		vs = append(vs, v)
+	}
+	// Longer values are replaced first, so that a value that contains
+	// another one is fully redacted.
+	sort.Slice(vs, func(i, j int) bool {
+		if len(vs[i]) != len(vs[j]) {
+			return len(vs[i]) > len(vs[j])
+		}
+		return vs[i] < vs[j]
+	})
+	oldnew := make([]string, 0, 2*len(vs))
+	for _, v := range vs {
+		oldnew = append(oldnew, v, Mask)
+	}
+	return &Redactor{values: vs, re: strings.NewReplacer(oldnew...)}
+}
+
+// String returns s with the secret values replaced with Mask.
+func (r *Redactor) String(s string) string {
+	if r == nil || len(r.values) == 0 {
+		return s
+	}
+	return r.re.Replace(s)
+}
+
+// Value returns a copy of v in which the strings, including those within
+// maps and slices decoded from JSON, are redacted.
+func (r *Redactor) Value(v interface{}) interface{} {
+	if r == nil || len(r.values) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case string:
+		return r.String(v)
+	case map[string]interface{}:
+		return r.Map(v)
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, val := range v {
+			c[i] = r.Value(val)
+		}
+		return c
+	case map[string]string:
+		c := make(map[string]string, len(v))
+		for key, val := range v {
+			c[key] = r.String(val)
+		}
+		return c
+	case []string:
+		c := make([]string, len(v))
+		for i, val := range v {
+			c[i] = r.String(val)
+		}
+		return c
+	}
+	return v
+}
+
+// Map returns a copy of m in which the values are redacted like Value.
+func (r *Redactor) Map(m map[string]interface{}) map[string]interface{} {
+	if m == nil || r == nil || len(r.values) == 0 {
+		return m
+	}
+	c := make(map[string]interface{}, len(m))
+	for key, val := range m {
+		c[key] = r.Value(val)
+	}
+	return c
+}
+
+// registry holds the values that are redacted by the package functions. The
+// values are counted since actions that run concurrently may use the same
+// secrets.
+var registry = struct {
+	sync.RWMutex
+	counts   map[string]int
+	redactor *Redactor
+}{
+	counts: make(map[string]int),
+}
+
+// Register adds the values to those that are redacted by String, Value and
+// Map until the returned function is called.
+func Register(values ...string) func() {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, v := range values {
+		registry.counts[v]++
+	}
+	updateRegistry()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			registry.Lock()
+			defer registry.Unlock()
+			for _, v := range values {
+				if registry.counts[v]--; registry.counts[v] <= 0 {
+					delete(registry.counts, v)
+				}
+			}
+			updateRegistry()
+		})
+	}
+}
+
+// updateRegistry must be called with the registry locked.
+func updateRegistry() {
+	values := make([]string, 0, len(registry.counts))
+	for v := range registry.counts {
+		values = append(values, v)
+	}
+	registry.redactor = New(values...)
+}
+
+func registered() *Redactor {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.redactor
+}
+
+// String returns s with the registered values replaced with Mask.
+func String(s string) string {
+	return registered().String(s)
+}
+
+// Value returns a copy of v in which the registered values are redacted.
+func Value(v interface{}) interface{} {
+	return registered().Value(v)
+}
+
+// Map returns a copy of m in which the registered values are redacted.
+func Map(m map[string]interface{}) map[string]interface{} {
+	return registered().Map(m)
+}
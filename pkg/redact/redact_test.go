@@ -0,0 +1,70 @@
+package redact
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) { TestingT(t) }
+
+type RedactSuite struct{}
+
+var _ = Suite(&RedactSuite{})
+
+func (s *RedactSuite) TestString(c *C) {
+	r := New("s3cr3t", "s3cr3t-key", "", "abc")
+	for _, tc := range []struct {
+		in  string
+		out string
+	}{
+		{"export RESTIC_PASSWORD=s3cr3t-key\n", "export RESTIC_PASSWORD=***\n"},
+		{"password: s3cr3t, again s3cr3t", "password: ***, again ***"},
+		// Short values are not redacted.
+		{"abc", "abc"},
+		{"nothing to redact", "nothing to redact"},
+	} {
+		c.Check(r.String(tc.in), Equals, tc.out)
+	}
+	var nr *Redactor
+	c.Check(nr.String("s3cr3t"), Equals, "s3cr3t")
+}
+
+func (s *RedactSuite) TestValue(c *C) {
+	r := New("s3cr3t")
+	in := map[string]interface{}{
+		"key":   "s3cr3t",
+		"ids":   []interface{}{"id-1", "s3cr3t"},
+		"count": 2,
+		"nested": map[string]interface{}{
+			"url": "s3://user:s3cr3t@bucket",
+		},
+	}
+	out := r.Map(in)
+	c.Assert(out, DeepEquals, map[string]interface{}{
+		"key":   "***",
+		"ids":   []interface{}{"id-1", "***"},
+		"count": 2,
+		"nested": map[string]interface{}{
+			"url": "s3://user:***@bucket",
+		},
+	})
+	// The input is not modified.
+	c.Assert(in["key"], Equals, "s3cr3t")
+	c.Assert(r.Map(nil), IsNil)
+}
+
+func (s *RedactSuite) TestRegister(c *C) {
+	c.Assert(String("s3cr3t"), Equals, "s3cr3t")
+	unregister1 := Register("s3cr3t")
+	unregister2 := Register("s3cr3t", "other-secret")
+	c.Assert(String("s3cr3t other-secret"), Equals, "*** ***")
+	unregister2()
+	// Calling the function twice has no effect.
+	unregister2()
+	c.Assert(String("s3cr3t other-secret"), Equals, "*** other-secret")
+	c.Assert(Map(map[string]interface{}{"key": "s3cr3t"}), DeepEquals, map[string]interface{}{"key": "***"})
+	unregister1()
+	c.Assert(String("s3cr3t"), Equals, "s3cr3t")
+}
@@ -22,7 +22,10 @@ func CreateCustomResources(ctx context.Context, config *rest.Config) error {
 	resources := []opkit.CustomResource{
 		crv1alpha1.ActionSetResource,
 		crv1alpha1.BlueprintResource,
+		crv1alpha1.BlueprintBindingResource,
 		crv1alpha1.ProfileResource,
+		crv1alpha1.RetentionPolicyResource,
+		crv1alpha1.ScheduleResource,
 	}
 	return opkit.CreateCustomResources(*opKitCTX, resources)
 }
@@ -127,3 +127,126 @@ func (s *ResourceSuite) TestBlueprintClient(c *C) {
 	_, err = cli.Blueprints(s.namespace).Get(name, emptyGetOptions)
 	c.Assert(err, NotNil)
 }
+
+func (s *ResourceSuite) TestScheduleClient(c *C) {
+	ctx := context.Background()
+	config, err := kube.LoadConfig()
+	c.Assert(err, IsNil)
+
+	err = CreateCustomResources(ctx, config)
+	c.Assert(err, IsNil)
+
+	name := "testschedule"
+	cli, err := crclientv1alpha1.NewForConfig(config)
+	c.Assert(err, IsNil)
+	sched := &crv1alpha1.Schedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	sched1, err := cli.Schedules(s.namespace).Create(sched)
+	c.Assert(err, IsNil)
+	c.Assert(sched, NotNil)
+
+	sched2, err := cli.Schedules(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(sched1, DeepEquals, sched2)
+
+	sched2.Spec = &crv1alpha1.ScheduleSpec{Cron: "@daily"}
+	sched3, err := cli.Schedules(s.namespace).Update(sched2)
+	c.Assert(err, IsNil)
+	c.Assert(sched1.Spec, IsNil)
+	c.Assert(sched3.Spec, NotNil)
+
+	sched4, err := cli.Schedules(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(sched4, DeepEquals, sched3)
+
+	err = cli.Schedules(s.namespace).Delete(name, nil)
+	c.Assert(err, IsNil)
+
+	_, err = cli.Schedules(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, NotNil)
+}
+
+func (s *ResourceSuite) TestRetentionPolicyClient(c *C) {
+	ctx := context.Background()
+	config, err := kube.LoadConfig()
+	c.Assert(err, IsNil)
+
+	err = CreateCustomResources(ctx, config)
+	c.Assert(err, IsNil)
+
+	name := "testretentionpolicy"
+	cli, err := crclientv1alpha1.NewForConfig(config)
+	c.Assert(err, IsNil)
+	rp := &crv1alpha1.RetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	rp1, err := cli.RetentionPolicies(s.namespace).Create(rp)
+	c.Assert(err, IsNil)
+	c.Assert(rp, NotNil)
+
+	rp2, err := cli.RetentionPolicies(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(rp1, DeepEquals, rp2)
+
+	rp2.Spec = &crv1alpha1.RetentionPolicySpec{Action: "backup", KeepLast: 1}
+	rp3, err := cli.RetentionPolicies(s.namespace).Update(rp2)
+	c.Assert(err, IsNil)
+	c.Assert(rp1.Spec, IsNil)
+	c.Assert(rp3.Spec, NotNil)
+
+	rp4, err := cli.RetentionPolicies(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(rp4, DeepEquals, rp3)
+
+	err = cli.RetentionPolicies(s.namespace).Delete(name, nil)
+	c.Assert(err, IsNil)
+
+	_, err = cli.RetentionPolicies(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, NotNil)
+}
+
+func (s *ResourceSuite) TestBlueprintBindingClient(c *C) {
+	ctx := context.Background()
+	config, err := kube.LoadConfig()
+	c.Assert(err, IsNil)
+
+	err = CreateCustomResources(ctx, config)
+	c.Assert(err, IsNil)
+
+	name := "testblueprintbinding"
+	cli, err := crclientv1alpha1.NewForConfig(config)
+	c.Assert(err, IsNil)
+	bb := &crv1alpha1.BlueprintBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	bb1, err := cli.BlueprintBindings(s.namespace).Create(bb)
+	c.Assert(err, IsNil)
+	c.Assert(bb, NotNil)
+
+	bb2, err := cli.BlueprintBindings(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(bb1, DeepEquals, bb2)
+
+	bb2.Spec = &crv1alpha1.BlueprintBindingSpec{Blueprint: "blueprint"}
+	bb3, err := cli.BlueprintBindings(s.namespace).Update(bb2)
+	c.Assert(err, IsNil)
+	c.Assert(bb1.Spec, IsNil)
+	c.Assert(bb3.Spec, NotNil)
+
+	bb4, err := cli.BlueprintBindings(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, IsNil)
+	c.Assert(bb4, DeepEquals, bb3)
+
+	err = cli.BlueprintBindings(s.namespace).Delete(name, nil)
+	c.Assert(err, IsNil)
+
+	_, err = cli.BlueprintBindings(s.namespace).Get(name, emptyGetOptions)
+	c.Assert(err, NotNil)
+}
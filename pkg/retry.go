@@ -0,0 +1,58 @@
+package kanister
+
+import (
+	"regexp"
+
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+
+	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+)
+
+// retryPolicy is the compiled form of a crv1alpha1.RetryPolicy.
+type retryPolicy struct {
+	maxAttempts int
+	backoff     backoff.Backoff
+	retryable   []*regexp.Regexp
+}
+
+func newRetryPolicy(rp *crv1alpha1.RetryPolicy) (*retryPolicy, error) {
+	if rp == nil {
+		return &retryPolicy{maxAttempts: 1}, nil
+	}
+	if rp.MaxAttempts < 0 {
+		return nil, errors.Errorf("Retry policy maxAttempts must be non-negative, got %d", rp.MaxAttempts)
+	}
+	p := &retryPolicy{maxAttempts: rp.MaxAttempts}
+	if p.maxAttempts == 0 {
+		p.maxAttempts = 1
+	}
+	if rp.MinBackoff != nil {
+		p.backoff.Min = rp.MinBackoff.Duration
+	}
+	if rp.MaxBackoff != nil {
+		p.backoff.Max = rp.MaxBackoff.Duration
+	}
+	for _, e := range rp.RetryableErrors {
+		re, err := regexp.Compile(e)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid retryable error pattern {%s}", e)
+		}
+		p.retryable = append(p.retryable, re)
+	}
+	return p, nil
+}
+
+// isRetryable returns true if err matches one of the policy's retryable
+// error patterns. Every error is retryable if no patterns were given.
+func (p *retryPolicy) isRetryable(err error) bool {
+	if len(p.retryable) == 0 {
+		return true
+	}
+	for _, re := range p.retryable {
+		if re.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
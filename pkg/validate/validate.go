@@ -2,11 +2,19 @@ package validate
 
 import (
 	"context"
+	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
 	"github.com/kanisterio/kanister/pkg/objectstore"
 	"github.com/kanisterio/kanister/pkg/param"
@@ -24,6 +32,13 @@ func ActionSet(as *crv1alpha1.ActionSet) error {
 		if err := actionSetStatus(as.Status); err != nil {
 			return err
 		}
+		// The phases of a dry run are rendered independently of each
+		// other.
+		if !as.Spec.DryRun {
+			if err := actionSetStatusActions(as.Status.Actions); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -32,6 +47,9 @@ func actionSetSpec(as *crv1alpha1.ActionSetSpec) error {
 	if as == nil {
 		return errorf("Spec must be non-nil")
 	}
+	if as.TTLSecondsAfterFinished != nil && *as.TTLSecondsAfterFinished < 0 {
+		return errorf("TTL seconds after finished must be non-negative, got %d", *as.TTLSecondsAfterFinished)
+	}
 	for _, a := range as.Actions {
 		if err := actionSpec(a); err != nil {
 			return err
@@ -57,6 +75,9 @@ func actionSpec(s crv1alpha1.ActionSpec) error {
 			return errorf("Not a known object Kind %s. Action %s must specify Resource name and API version", s.Object.Kind, s.Name)
 		}
 	}
+	if s.Timeout != nil && s.Timeout.Duration <= 0 {
+		return errorf("Action %s timeout must be positive, got %s", s.Name, s.Timeout.Duration)
+	}
 	return nil
 }
 
@@ -64,23 +85,37 @@ func actionSetStatus(as *crv1alpha1.ActionSetStatus) error {
 	if as == nil {
 		return nil
 	}
-	if err := actionSetStatusActions(as.Actions); err != nil {
-		return err
-	}
 	saw := map[crv1alpha1.State]bool{
-		crv1alpha1.StatePending:  false,
-		crv1alpha1.StateRunning:  false,
-		crv1alpha1.StateFailed:   false,
-		crv1alpha1.StateComplete: false,
+		crv1alpha1.StatePending:   false,
+		crv1alpha1.StateQueued:    false,
+		crv1alpha1.StateRunning:   false,
+		crv1alpha1.StateFailed:    false,
+		crv1alpha1.StateComplete:  false,
+		crv1alpha1.StateTimedOut:  false,
+		crv1alpha1.StateSkipped:   false,
+		crv1alpha1.StateCancelled: false,
 	}
 	for _, a := range as.Actions {
-		for _, p := range a.Phases {
+		phases := a.Phases
+		if a.DeferPhase != nil {
+			phases = append(phases[:len(phases):len(phases)], *a.DeferPhase)
+		}
+		for _, p := range phases {
 			if _, ok := saw[p.State]; !ok {
 				return errorf("Action has unknown state '%s'", p.State)
 			}
 			for s := range saw {
 				saw[s] = saw[s] || (p.State == s)
 			}
+			if p.Error != "" && p.State != crv1alpha1.StateFailed && p.State != crv1alpha1.StateTimedOut {
+				return errorf("Phase %s has an error but is %s", p.Name, p.State)
+			}
+			if endsBeforeStart(p.StartTime, p.EndTime) {
+				return errorf("Phase %s ended before it started", p.Name)
+			}
+		}
+		if endsBeforeStart(a.StartTime, a.EndTime) {
+			return errorf("Action %s ended before it started", a.Name)
 		}
 	}
 	if _, ok := saw[as.State]; !ok {
@@ -91,29 +126,395 @@ func actionSetStatus(as *crv1alpha1.ActionSetStatus) error {
 			return errorf("ActionSet cannot be complete if any actions are not complete")
 		}
 	}
-	return nil
+	if endsBeforeStart(as.StartTime, as.EndTime) {
+		return errorf("ActionSet ended before it started")
+	}
+	return actionSetConditions(as.Conditions)
 }
 
+// actionSetStatusActions checks that the phases of each action that are not
+// pending only started once the phases they depend on completed or were
+// skipped. If none of the phases has dependencies, each phase depends on the
+// one before it.
 func actionSetStatusActions(as []crv1alpha1.ActionStatus) error {
 	for _, a := range as {
-		var sawNotComplete bool
-		var lastNonComplete crv1alpha1.State
+		var hasDeps bool
+		states := make(map[string]crv1alpha1.State, len(a.Phases))
 		for _, p := range a.Phases {
-			if sawNotComplete && p.State != crv1alpha1.StatePending {
-				return errorf("Phases after a %s one must be pending", lastNonComplete)
+			hasDeps = hasDeps || len(p.DependsOn) > 0
+			states[p.Name] = p.State
+		}
+		for i, p := range a.Phases {
+			if p.State == crv1alpha1.StatePending {
+				continue
 			}
-			if !sawNotComplete {
-				lastNonComplete = p.State
+			if !hasDeps {
+				if i > 0 && !phaseDone(a.Phases[i-1].State) {
+					return errorf("Phases after a %s one must be pending", a.Phases[i-1].State)
+				}
+				continue
 			}
-			sawNotComplete = p.State != crv1alpha1.StateComplete
+			for _, d := range p.DependsOn {
+				state, ok := states[d]
+				if !ok {
+					return errorf("Phase %s depends on unknown phase %s", p.Name, d)
+				}
+				if !phaseDone(state) {
+					return errorf("Phase %s must be pending since phase %s that it depends on is %s", p.Name, d, state)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// phaseDone returns whether the phases that depend on a phase in the state
+// may start.
+func phaseDone(s crv1alpha1.State) bool {
+	return s == crv1alpha1.StateComplete || s == crv1alpha1.StateSkipped
+}
+
+func endsBeforeStart(start, end *metav1.Time) bool {
+	return start != nil && end != nil && end.Before(start)
+}
+
+func actionSetConditions(cs []crv1alpha1.ActionSetCondition) error {
+	saw := make(map[crv1alpha1.ActionSetConditionType]bool, len(cs))
+	for _, c := range cs {
+		if saw[c.Type] {
+			return errorf("ActionSet has more than one '%s' condition", c.Type)
+		}
+		saw[c.Type] = true
+		switch c.Status {
+		case corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown:
+		default:
+			return errorf("Condition '%s' has unknown status '%s'", c.Type, c.Status)
 		}
 	}
 	return nil
 }
 
 // Blueprint function validates the Blueprint and returns an error if it is invalid.
+// Blueprints that use the actions of other Blueprints must be expanded with
+// kanister.ExpandBlueprint first.
 func Blueprint(bp *crv1alpha1.Blueprint) error {
-	// TODO: Add blueprint validation.
+	if bp == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(bp.Actions) {
+		if err := blueprintAction(name, bp.Actions[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BlueprintTemplates statically checks the templates of the Blueprint against
+// the template parameters. It returns an error naming the action, phase and
+// argument of the first template that refers to a field that does not exist
+// or to the output of a phase that may not have completed when it is
+// rendered.
+func BlueprintTemplates(bp *crv1alpha1.Blueprint) error {
+	if bp == nil {
+		return nil
+	}
+	for _, name := range sortedKeys(bp.Actions) {
+		if err := actionTemplates(name, bp.Actions[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func actionTemplates(name string, a *crv1alpha1.BlueprintAction) error {
+	if a == nil {
+		return nil
+	}
+	preceding, err := kanister.PrecedingPhases(*a)
+	if err != nil {
+		return errorf("Action %s has invalid phase dependencies: %s", name, err)
+	}
+	all := make(param.PhaseOutputs, len(a.Phases)+1)
+	for i, p := range a.Phases {
+		po := make(param.PhaseOutputs, len(preceding[i]))
+		for _, j := range preceding[i] {
+			po[a.Phases[j].Name] = phaseOutputs(a.Phases[j])
+		}
+		if err := phaseTemplates(p, po); err != nil {
+			return errorf("Action %s phase %s %s", name, p.Name, err)
+		}
+		all[p.Name] = phaseOutputs(p)
+	}
+	if a.DeferPhase != nil {
+		if err := phaseTemplates(*a.DeferPhase, all); err != nil {
+			return errorf("Action %s defer phase %s %s", name, a.DeferPhase.Name, err)
+		}
+		all[a.DeferPhase.Name] = phaseOutputs(*a.DeferPhase)
+	}
+	for _, k := range sortedKeys(a.OutputArtifacts) {
+		if err := param.CheckTemplates(a.OutputArtifacts[k], all); err != nil {
+			return errorf("Action %s output artifact %s: %s", name, k, err)
+		}
+	}
+	return nil
+}
+
+func phaseTemplates(p crv1alpha1.BlueprintPhase, po param.PhaseOutputs) error {
+	for _, k := range sortedKeys(p.Args) {
+		if err := param.CheckTemplates(p.Args[k], po); err != nil {
+			return errors.Wrapf(err, "arg %s", k)
+		}
+	}
+	for _, k := range sortedKeys(p.ObjectRefs) {
+		if err := param.CheckTemplates(p.ObjectRefs[k], po); err != nil {
+			return errors.Wrapf(err, "object ref %s", k)
+		}
+	}
+	if err := param.CheckTemplates(p.If, po); err != nil {
+		return errors.Wrap(err, "if")
+	}
+	return nil
+}
+
+// phaseOutputs returns the output keys declared by the function of the phase,
+// or nil if it does not declare them.
+func phaseOutputs(p crv1alpha1.BlueprintPhase) []string {
+	f, ok := kanister.GetFunc(p.Func)
+	if !ok {
+		return nil
+	}
+	fo, ok := f.(kanister.FuncOutputs)
+	if !ok {
+		return nil
+	}
+	if keys := fo.Outputs(); keys != nil {
+		return keys
+	}
+	return []string{}
+}
+
+// sortedKeys returns the keys of a map with string keys in order.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func blueprintAction(name string, a *crv1alpha1.BlueprintAction) error {
+	if a == nil {
+		return nil
+	}
+	if a.Uses != "" {
+		return errorf("Action %s uses %s and must be expanded first", name, a.Uses)
+	}
+	if a.Parallelism < 0 {
+		return errorf("Action %s parallelism must be non-negative, got %d", name, a.Parallelism)
+	}
+	if a.Timeout != nil && a.Timeout.Duration <= 0 {
+		return errorf("Action %s timeout must be positive, got %s", name, a.Timeout.Duration)
+	}
+	for _, o := range sortedKeys(a.Options) {
+		if err := optionSpec(a.Options[o]); err != nil {
+			return errorf("Action %s option %s is invalid: %s", name, o, err)
+		}
+	}
+	phases := a.Phases
+	if a.DeferPhase != nil {
+		phases = append(phases[:len(phases):len(phases)], *a.DeferPhase)
+	}
+	seen := make(map[string]bool, len(phases))
+	for _, p := range phases {
+		if seen[p.Name] {
+			return errorf("Action %s phase name %s is not unique", name, p.Name)
+		}
+		seen[p.Name] = true
+		if err := blueprintPhase(p); err != nil {
+			return errorf("Action %s phase %s is invalid: %s", name, p.Name, err)
+		}
+	}
+	if err := kanister.ValidatePhaseDependencies(*a); err != nil {
+		return errorf("Action %s has invalid phase dependencies: %s", name, err)
+	}
+	if err := param.ParseTemplates(a.OutputArtifacts); err != nil {
+		return errorf("Action %s has invalid output artifacts: %s", name, err)
+	}
+	for _, k := range sortedKeys(a.OutputArtifacts) {
+		if err := sensitiveKeys(k, a.OutputArtifacts[k]); err != nil {
+			return errorf("Action %s output artifact %s is invalid: %s", name, k, err)
+		}
+	}
+	return nil
+}
+
+// sensitiveKeys checks that the sensitive keys of an artifact are among its
+// keys and can be stored in a Secret.
+func sensitiveKeys(name string, a crv1alpha1.Artifact) error {
+	for _, k := range a.Sensitive {
+		if _, ok := a.KeyValue[k]; !ok {
+			return errors.Errorf("Sensitive key %s is not a key of the artifact", k)
+		}
+		if errs := validation.IsConfigMapKey(param.ArtifactSecretKey(name, k)); len(errs) > 0 {
+			return errors.Errorf("Sensitive key %s cannot be stored in a secret: %s", k, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func optionSpec(o crv1alpha1.OptionSpec) error {
+	switch o.Type {
+	case "", crv1alpha1.OptionTypeString, crv1alpha1.OptionTypeInt, crv1alpha1.OptionTypeBool, crv1alpha1.OptionTypeDuration:
+	default:
+		return errors.Errorf("Unknown type %s", o.Type)
+	}
+	if o.Default == "" {
+		return nil
+	}
+	if o.Required {
+		return errors.New("Required option must not have a default")
+	}
+	return param.CheckOptionValue(o.Type, o.Default)
+}
+
+func blueprintPhase(p crv1alpha1.BlueprintPhase) error {
+	if p.Uses != "" {
+		return errors.Errorf("Phase uses %s and must be expanded first", p.Uses)
+	}
+	f, ok := kanister.GetFunc(p.Func)
+	if !ok {
+		return errors.Errorf("Function %s is not registered", p.Func)
+	}
+	for _, arg := range f.RequiredArgs() {
+		if _, ok := p.Args[arg]; !ok {
+			return errors.Errorf("Required argument %s of function %s is missing", arg, p.Func)
+		}
+	}
+	if p.Timeout != nil && p.Timeout.Duration <= 0 {
+		return errors.Errorf("Timeout must be positive, got %s", p.Timeout.Duration)
+	}
+	if err := retryPolicy(p.RetryPolicy); err != nil {
+		return errors.Wrap(err, "Invalid retry policy")
+	}
+	if err := param.ParseTemplates(p.Args); err != nil {
+		return err
+	}
+	if err := param.ParseTemplates(p.ObjectRefs); err != nil {
+		return err
+	}
+	return param.ParseTemplates(p.If)
+}
+
+func retryPolicy(rp *crv1alpha1.RetryPolicy) error {
+	if rp == nil {
+		return nil
+	}
+	if rp.MaxAttempts < 0 {
+		return errors.Errorf("MaxAttempts must be non-negative, got %d", rp.MaxAttempts)
+	}
+	if rp.MinBackoff != nil && rp.MinBackoff.Duration < 0 {
+		return errors.Errorf("MinBackoff must be non-negative, got %s", rp.MinBackoff.Duration)
+	}
+	if rp.MaxBackoff != nil && rp.MaxBackoff.Duration < 0 {
+		return errors.Errorf("MaxBackoff must be non-negative, got %s", rp.MaxBackoff.Duration)
+	}
+	if rp.MinBackoff != nil && rp.MaxBackoff != nil && rp.MinBackoff.Duration > rp.MaxBackoff.Duration {
+		return errors.Errorf("MinBackoff %s must not exceed MaxBackoff %s", rp.MinBackoff.Duration, rp.MaxBackoff.Duration)
+	}
+	for _, e := range rp.RetryableErrors {
+		if _, err := regexp.Compile(e); err != nil {
+			return errors.Wrapf(err, "Invalid retryable error pattern %s", e)
+		}
+	}
+	return nil
+}
+
+// Schedule function validates the Schedule and returns an error if it is invalid.
+func Schedule(s *crv1alpha1.Schedule) error {
+	if s.Spec == nil {
+		return errorf("Spec must be non-nil")
+	}
+	if _, err := cron.ParseStandard(s.Spec.Cron); err != nil {
+		return errorf("Invalid cron expression %q: %s", s.Spec.Cron, err)
+	}
+	if s.Spec.ActionSetTemplate == nil {
+		return errorf("ActionSet template must be non-nil")
+	}
+	if err := actionSetSpec(s.Spec.ActionSetTemplate); err != nil {
+		return err
+	}
+	switch s.Spec.ConcurrencyPolicy {
+	case "", crv1alpha1.ConcurrencyPolicyAllow, crv1alpha1.ConcurrencyPolicyForbid, crv1alpha1.ConcurrencyPolicyReplace:
+	default:
+		return errorf("Unknown concurrency policy %s", s.Spec.ConcurrencyPolicy)
+	}
+	switch s.Spec.MissedRunPolicy {
+	case "", crv1alpha1.MissedRunPolicyRunOnce, crv1alpha1.MissedRunPolicySkip:
+	default:
+		return errorf("Unknown missed run policy %s", s.Spec.MissedRunPolicy)
+	}
+	if s.Spec.HistoryLimit < 0 {
+		return errorf("History limit must be non-negative, got %d", s.Spec.HistoryLimit)
+	}
+	return nil
+}
+
+// RetentionPolicy function validates the RetentionPolicy and returns an
+// error if it is invalid.
+func RetentionPolicy(rp *crv1alpha1.RetentionPolicy) error {
+	if rp.Spec == nil {
+		return errorf("Spec must be non-nil")
+	}
+	if rp.Spec.Action == "" {
+		return errorf("Action must be set")
+	}
+	deleteAction := rp.Spec.DeleteAction
+	if deleteAction == "" {
+		deleteAction = crv1alpha1.DefaultDeleteAction
+	}
+	if rp.Spec.Action == deleteAction {
+		return errorf("Delete action must differ from the action %s", rp.Spec.Action)
+	}
+	if rp.Spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(rp.Spec.Selector); err != nil {
+			return errorf("Invalid selector: %s", err)
+		}
+	}
+	for rule, n := range map[string]int{
+		"keepLast":    rp.Spec.KeepLast,
+		"keepDaily":   rp.Spec.KeepDaily,
+		"keepWeekly":  rp.Spec.KeepWeekly,
+		"keepMonthly": rp.Spec.KeepMonthly,
+	} {
+		if n < 0 {
+			return errorf("%s must be non-negative, got %d", rule, n)
+		}
+	}
+	if rp.Spec.MaxAge != nil && rp.Spec.MaxAge.Duration <= 0 {
+		return errorf("Max age must be positive, got %s", rp.Spec.MaxAge.Duration)
+	}
+	if rp.Spec.KeepLast+rp.Spec.KeepDaily+rp.Spec.KeepWeekly+rp.Spec.KeepMonthly == 0 && rp.Spec.MaxAge == nil {
+		return errorf("At least one keep rule or a max age must be set")
+	}
+	return nil
+}
+
+// BlueprintBinding function validates the BlueprintBinding and returns an
+// error if it is invalid.
+func BlueprintBinding(bb *crv1alpha1.BlueprintBinding) error {
+	if bb.Spec == nil {
+		return errorf("Spec must be non-nil")
+	}
+	if bb.Spec.Blueprint == "" {
+		return errorf("Blueprint must be set")
+	}
+	if bb.Spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(bb.Spec.Selector); err != nil {
+			return errorf("Invalid selector: %s", err)
+		}
+	}
 	return nil
 }
 
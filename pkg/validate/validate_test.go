@@ -1,13 +1,18 @@
 package validate
 
 import (
-	"github.com/kanisterio/kanister/pkg/param"
+	"context"
 	"testing"
+	"time"
 
 	. "gopkg.in/check.v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	kanister "github.com/kanisterio/kanister/pkg"
 	crv1alpha1 "github.com/kanisterio/kanister/pkg/apis/cr/v1alpha1"
+	"github.com/kanisterio/kanister/pkg/param"
+	"github.com/kanisterio/kanister/pkg/testutil"
 )
 
 // Hook up gocheck into the "go test" runner.
@@ -39,6 +44,18 @@ func (s *ValidateSuite) TestActionSet(c *C) {
 			},
 			checker: IsNil,
 		},
+		{
+			as: &crv1alpha1.ActionSet{
+				Spec: &crv1alpha1.ActionSetSpec{TTLSecondsAfterFinished: int32Ptr(0)},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSet{
+				Spec: &crv1alpha1.ActionSetSpec{TTLSecondsAfterFinished: int32Ptr(-1)},
+			},
+			checker: NotNil,
+		},
 		{
 			as: &crv1alpha1.ActionSet{
 				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1"},
@@ -301,6 +318,24 @@ func (s *ValidateSuite) TestActionSet(c *C) {
 				},
 			},
 			checker: IsNil,
+		},
+		// Non-positive timeout
+		{
+			as: &crv1alpha1.ActionSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns1"},
+				Spec: &crv1alpha1.ActionSetSpec{
+					Actions: []crv1alpha1.ActionSpec{
+						crv1alpha1.ActionSpec{
+							Object: crv1alpha1.ObjectReference{
+								Name: "foo",
+								Kind: param.PVCKind,
+							},
+							Timeout: &metav1.Duration{},
+						},
+					},
+				},
+			},
+			checker: NotNil,
 		}, // No object specified
 		{
 			as: &crv1alpha1.ActionSet{
@@ -456,13 +491,989 @@ func (s *ValidateSuite) TestActionSetStatus(c *C) {
 			},
 			checker: NotNil,
 		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateFailed,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateTimedOut,
+							},
+							crv1alpha1.Phase{
+								State: crv1alpha1.StatePending,
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateComplete,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateComplete,
+							},
+						},
+						DeferPhase: &crv1alpha1.Phase{
+							State: crv1alpha1.StatePending,
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateFailed,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateFailed,
+							},
+						},
+						DeferPhase: &crv1alpha1.Phase{
+							State: crv1alpha1.StateRunning,
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateSkipped,
+							},
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateRunning,
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State: crv1alpha1.StateRunning,
+								Error: "some error",
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateFailed,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State:     crv1alpha1.StateFailed,
+								StartTime: &metav1.Time{Time: time.Unix(100, 0)},
+								EndTime:   &metav1.Time{Time: time.Unix(200, 0)},
+								Error:     "some error",
+							},
+						},
+						StartTime: &metav1.Time{Time: time.Unix(100, 0)},
+						EndTime:   &metav1.Time{Time: time.Unix(200, 0)},
+						Error:     "some error",
+					},
+				},
+				StartTime: &metav1.Time{Time: time.Unix(100, 0)},
+				EndTime:   &metav1.Time{Time: time.Unix(200, 0)},
+				Conditions: []crv1alpha1.ActionSetCondition{
+					{Type: crv1alpha1.ActionSetConditionRunning, Status: corev1.ConditionFalse},
+					{Type: crv1alpha1.ActionSetConditionFailed, Status: corev1.ConditionTrue},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{
+					crv1alpha1.ActionStatus{
+						Phases: []crv1alpha1.Phase{
+							crv1alpha1.Phase{
+								State:     crv1alpha1.StateComplete,
+								StartTime: &metav1.Time{Time: time.Unix(200, 0)},
+								EndTime:   &metav1.Time{Time: time.Unix(100, 0)},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State:     crv1alpha1.StateComplete,
+				StartTime: &metav1.Time{Time: time.Unix(200, 0)},
+				EndTime:   &metav1.Time{Time: time.Unix(100, 0)},
+			},
+			checker: NotNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Conditions: []crv1alpha1.ActionSetCondition{
+					{Type: crv1alpha1.ActionSetConditionRunning, Status: corev1.ConditionTrue},
+					{Type: crv1alpha1.ActionSetConditionRunning, Status: corev1.ConditionFalse},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			as: &crv1alpha1.ActionSetStatus{
+				State: crv1alpha1.StateRunning,
+				Conditions: []crv1alpha1.ActionSetCondition{
+					{Type: crv1alpha1.ActionSetConditionRunning, Status: "Maybe"},
+				},
+			},
+			checker: NotNil,
+		},
 	} {
 		err := actionSetStatus(tc.as)
 		c.Check(err, tc.checker)
 	}
 }
 
+func (s *ValidateSuite) TestActionSetStatusActions(c *C) {
+	phase := func(name string, state crv1alpha1.State, deps ...string) crv1alpha1.Phase {
+		return crv1alpha1.Phase{Name: name, State: state, DependsOn: deps}
+	}
+	for _, tc := range []struct {
+		phases  []crv1alpha1.Phase
+		checker Checker
+	}{
+		// Phases without dependencies run in order.
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateComplete),
+				phase("b", crv1alpha1.StateSkipped),
+				phase("c", crv1alpha1.StateRunning),
+				phase("d", crv1alpha1.StatePending),
+			},
+			checker: IsNil,
+		},
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateFailed),
+				phase("b", crv1alpha1.StateComplete),
+			},
+			checker: NotNil,
+		},
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateRunning),
+				phase("b", crv1alpha1.StateRunning),
+			},
+			checker: NotNil,
+		},
+		// Phases run once the phases they depend on are done.
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateRunning),
+				phase("b", crv1alpha1.StateFailed),
+				phase("c", crv1alpha1.StatePending, "a", "b"),
+			},
+			checker: IsNil,
+		},
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateComplete),
+				phase("b", crv1alpha1.StateSkipped),
+				phase("c", crv1alpha1.StateComplete, "a", "b"),
+				phase("d", crv1alpha1.StateRunning, "c"),
+			},
+			checker: IsNil,
+		},
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateComplete),
+				phase("b", crv1alpha1.StateRunning),
+				phase("c", crv1alpha1.StateRunning, "a", "b"),
+			},
+			checker: NotNil,
+		},
+		{
+			phases: []crv1alpha1.Phase{
+				phase("a", crv1alpha1.StateComplete),
+				phase("b", crv1alpha1.StateCancelled, "missing"),
+			},
+			checker: NotNil,
+		},
+	} {
+		as := &crv1alpha1.ActionSet{
+			Spec: &crv1alpha1.ActionSetSpec{
+				Actions: []crv1alpha1.ActionSpec{
+					{Name: "backup", Object: crv1alpha1.ObjectReference{Kind: param.DeploymentKind}},
+				},
+			},
+			Status: &crv1alpha1.ActionSetStatus{
+				State:   crv1alpha1.StateRunning,
+				Actions: []crv1alpha1.ActionStatus{{Name: "backup", Phases: tc.phases}},
+			},
+		}
+		err := ActionSet(as)
+		c.Check(err, tc.checker, Commentf("%#v", tc.phases))
+
+		// The phases of a dry run are rendered independently of each
+		// other.
+		as.Spec.DryRun = true
+		c.Check(ActionSet(as), IsNil)
+	}
+}
+
+const requiredArgFuncName = "validateRequiredArgFunc"
+
+type requiredArgFunc struct{}
+
+func (requiredArgFunc) Name() string {
+	return requiredArgFuncName
+}
+
+func (requiredArgFunc) RequiredArgs() []string {
+	return []string{"namespace"}
+}
+
+func (requiredArgFunc) Outputs() []string {
+	return []string{"path"}
+}
+
+func (requiredArgFunc) Exec(context.Context, param.TemplateParams, map[string]interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func init() {
+	kanister.Register(requiredArgFunc{})
+}
+
 func (s *ValidateSuite) TestBlueprint(c *C) {
 	err := Blueprint(nil)
 	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		bp      *crv1alpha1.Blueprint
+		checker Checker
+	}{
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Parallelism: 2,
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName},
+							{Name: "b", Func: testutil.ArgFuncName},
+							{Name: "c", Func: testutil.ArgFuncName, DependsOn: []string{"a", "b"}},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Parallelism: -1,
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName, DependsOn: []string{"b"}},
+							{Name: "b", Func: testutil.ArgFuncName, DependsOn: []string{"a"}},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Timeout: &metav1.Duration{Duration: -time.Minute},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Valid retry policy and timeout
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{
+								Name:    "a",
+								Func:    testutil.ArgFuncName,
+								Timeout: &metav1.Duration{Duration: time.Minute},
+								RetryPolicy: &crv1alpha1.RetryPolicy{
+									MaxAttempts:     3,
+									MinBackoff:      &metav1.Duration{Duration: time.Second},
+									MaxBackoff:      &metav1.Duration{Duration: time.Minute},
+									RetryableErrors: []string{"^transient"},
+								},
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			// Negative phase timeout
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName, Timeout: &metav1.Duration{Duration: -time.Minute}},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Negative maximum number of attempts
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName, RetryPolicy: &crv1alpha1.RetryPolicy{MaxAttempts: -1}},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Invalid retryable error pattern
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName, RetryPolicy: &crv1alpha1.RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"("}}},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Minimum backoff exceeds the maximum
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{
+								Name: "a",
+								Func: testutil.ArgFuncName,
+								RetryPolicy: &crv1alpha1.RetryPolicy{
+									MaxAttempts: 3,
+									MinBackoff:  &metav1.Duration{Duration: time.Minute},
+									MaxBackoff:  &metav1.Duration{Duration: time.Second},
+								},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Unregistered function
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: "NoSuchFunc"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Duplicate phase names
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName},
+							{Name: "a", Func: testutil.ArgFuncName},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// The defer phase shares the names of the action's phases.
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName},
+						},
+						DeferPhase: &crv1alpha1.BlueprintPhase{Name: "a", Func: testutil.ArgFuncName},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{
+								Name: "a",
+								Func: requiredArgFuncName,
+								Args: map[string]interface{}{"namespace": "{{ .Namespace.Name }}"},
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			// Missing required argument
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: requiredArgFuncName},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Template that does not parse in a nested argument
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{
+								Name: "a",
+								Func: testutil.ArgFuncName,
+								Args: map[string]interface{}{
+									"command": []interface{}{"echo", "{{ .Options.foo "},
+								},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Unknown sprig function
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Phases: []crv1alpha1.BlueprintPhase{
+							{Name: "a", Func: testutil.ArgFuncName, If: "{{ noSuchFunc .Options }}"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						OutputArtifacts: map[string]crv1alpha1.Artifact{
+							"cloudObject": crv1alpha1.Artifact{
+								KeyValue: map[string]string{
+									"path": "{{ .Phases.a.Output.path ",
+								},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Options: map[string]crv1alpha1.OptionSpec{
+							"database": {Required: true, Description: "Database to back up"},
+							"replicas": {Type: crv1alpha1.OptionTypeInt, Default: "1"},
+							"quiesce":  {Type: crv1alpha1.OptionTypeBool, Default: "true"},
+							"timeout":  {Type: crv1alpha1.OptionTypeDuration},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Options: map[string]crv1alpha1.OptionSpec{
+							"ratio": {Type: "float"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Options: map[string]crv1alpha1.OptionSpec{
+							"replicas": {Type: crv1alpha1.OptionTypeInt, Default: "one"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						Options: map[string]crv1alpha1.OptionSpec{
+							"database": {Required: true, Default: "mysql"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						OutputArtifacts: map[string]crv1alpha1.Artifact{
+							"snapshot": {
+								KeyValue:  map[string]string{"path": "/snap", "encryptionKey": "{{ .Options.key }}"},
+								Sensitive: []string{"encryptionKey"},
+							},
+						},
+					},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						OutputArtifacts: map[string]crv1alpha1.Artifact{
+							"snapshot": {
+								KeyValue:  map[string]string{"path": "/snap"},
+								Sensitive: []string{"encryptionKey"},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			bp: &crv1alpha1.Blueprint{
+				Actions: map[string]*crv1alpha1.BlueprintAction{
+					"backup": &crv1alpha1.BlueprintAction{
+						OutputArtifacts: map[string]crv1alpha1.Artifact{
+							"snapshot/cloud": {
+								KeyValue:  map[string]string{"encryptionKey": "key"},
+								Sensitive: []string{"encryptionKey"},
+							},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+	} {
+		err := Blueprint(tc.bp)
+		c.Check(err, tc.checker)
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+}
+
+func (s *ValidateSuite) TestBlueprintTemplates(c *C) {
+	err := BlueprintTemplates(nil)
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		action  *crv1alpha1.BlueprintAction
+		checker Checker
+	}{
+		{
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "backup",
+						Func: requiredArgFuncName,
+						Args: map[string]interface{}{
+							"namespace": "{{ .StatefulSet.Namespace }}",
+							"pods":      []interface{}{"{{ index .StatefulSet.Pods 0 }}"},
+						},
+						ObjectRefs: map[string]crv1alpha1.ObjectReference{
+							"sts": {Name: "{{ .StatefulSet.Name }}"},
+						},
+					},
+					{
+						Name: "notify",
+						Func: testutil.ArgFuncName,
+						Args: map[string]interface{}{"path": "{{ .Phases.backup.Output.path }}"},
+						If:   `{{ eq .Options.notify "true" }}`,
+					},
+				},
+				DeferPhase: &crv1alpha1.BlueprintPhase{
+					Name: "cleanup",
+					Func: testutil.ArgFuncName,
+					Args: map[string]interface{}{"key": "{{ .Phases.notify.Output.key }}"},
+				},
+				OutputArtifacts: map[string]crv1alpha1.Artifact{
+					"cloudObject": {KeyValue: map[string]string{"path": "{{ .Phases.backup.Output.path }}"}},
+				},
+			},
+			checker: IsNil,
+		},
+		{
+			// Misspelled field
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "backup",
+						Func: testutil.ArgFuncName,
+						Args: map[string]interface{}{"pods": "{{ .StatefulSet.Podz }}"},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Misspelled field in an object ref
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "backup",
+						Func: testutil.ArgFuncName,
+						ObjectRefs: map[string]crv1alpha1.ObjectReference{
+							"sts": {Name: "{{ .StatefulSet.Nmae }}"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Output key that the function does not declare
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "backup", Func: requiredArgFuncName},
+					{
+						Name: "notify",
+						Func: testutil.ArgFuncName,
+						Args: map[string]interface{}{"path": "{{ .Phases.backup.Output.backupPath }}"},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Output of a later phase
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{
+						Name: "notify",
+						Func: testutil.ArgFuncName,
+						Args: map[string]interface{}{"key": "{{ .Phases.backup.Output.key }}"},
+					},
+					{Name: "backup", Func: testutil.OutputFuncName},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			// Output of a phase that runs concurrently
+			action: &crv1alpha1.BlueprintAction{
+				Phases: []crv1alpha1.BlueprintPhase{
+					{Name: "backup", Func: testutil.OutputFuncName},
+					{
+						Name:      "notify",
+						Func:      testutil.ArgFuncName,
+						Args:      map[string]interface{}{"key": "{{ .Phases.backup.Output.key }}"},
+						DependsOn: []string{},
+					},
+					{Name: "done", Func: testutil.ArgFuncName, DependsOn: []string{"backup", "notify"}},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			action: &crv1alpha1.BlueprintAction{
+				OutputArtifacts: map[string]crv1alpha1.Artifact{
+					"cloudObject": {KeyValue: map[string]string{"path": "{{ .Phases.backup.Output.path }}"}},
+				},
+			},
+			checker: NotNil,
+		},
+	} {
+		bp := &crv1alpha1.Blueprint{
+			Actions: map[string]*crv1alpha1.BlueprintAction{"backup": tc.action},
+		}
+		err := BlueprintTemplates(bp)
+		c.Check(err, tc.checker)
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+}
+
+func (s *ValidateSuite) TestSchedule(c *C) {
+	template := func() *crv1alpha1.ActionSetSpec {
+		return &crv1alpha1.ActionSetSpec{
+			Actions: []crv1alpha1.ActionSpec{
+				{
+					Name:      "backup",
+					Blueprint: "mysql-blueprint",
+					Object: crv1alpha1.ObjectReference{
+						Kind: param.StatefulSetKind,
+					},
+				},
+			},
+		}
+	}
+	for _, tc := range []struct {
+		spec    *crv1alpha1.ScheduleSpec
+		checker Checker
+	}{
+		{
+			spec:    nil,
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "0 2 * * *",
+				ActionSetTemplate: template(),
+			},
+			checker: IsNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "@daily",
+				ActionSetTemplate: template(),
+				ConcurrencyPolicy: crv1alpha1.ConcurrencyPolicyReplace,
+				MissedRunPolicy:   crv1alpha1.MissedRunPolicySkip,
+				HistoryLimit:      3,
+			},
+			checker: IsNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "0 2 * *",
+				ActionSetTemplate: template(),
+			},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron: "0 2 * * *",
+			},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron: "0 2 * * *",
+				ActionSetTemplate: &crv1alpha1.ActionSetSpec{
+					Actions: []crv1alpha1.ActionSpec{
+						{
+							Name:   "backup",
+							Object: crv1alpha1.ObjectReference{Kind: "unknown"},
+						},
+					},
+				},
+			},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "0 2 * * *",
+				ActionSetTemplate: template(),
+				ConcurrencyPolicy: "sometimes",
+			},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "0 2 * * *",
+				ActionSetTemplate: template(),
+				MissedRunPolicy:   "runAll",
+			},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.ScheduleSpec{
+				Cron:              "0 2 * * *",
+				ActionSetTemplate: template(),
+				HistoryLimit:      -1,
+			},
+			checker: NotNil,
+		},
+	} {
+		err := Schedule(&crv1alpha1.Schedule{Spec: tc.spec})
+		c.Check(err, tc.checker)
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+}
+
+func (s *ValidateSuite) TestRetentionPolicy(c *C) {
+	for _, tc := range []struct {
+		spec    *crv1alpha1.RetentionPolicySpec
+		checker Checker
+	}{
+		{
+			spec:    nil,
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup", KeepLast: 3},
+			checker: IsNil,
+		},
+		{
+			spec: &crv1alpha1.RetentionPolicySpec{
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{crv1alpha1.ScheduleLabel: "nightly"}},
+				Action:       "backup",
+				DeleteAction: "cleanup",
+				KeepDaily:    7,
+				KeepWeekly:   4,
+				KeepMonthly:  12,
+				MaxAge:       &metav1.Duration{Duration: 365 * 24 * time.Hour},
+			},
+			checker: IsNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup", MaxAge: &metav1.Duration{Duration: time.Hour}},
+			checker: IsNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{KeepLast: 3},
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "delete", KeepLast: 3},
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup", DeleteAction: "backup", KeepLast: 3},
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup"},
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup", KeepLast: 3, KeepWeekly: -1},
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.RetentionPolicySpec{Action: "backup", MaxAge: &metav1.Duration{}},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.RetentionPolicySpec{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}}},
+				Action:   "backup",
+				KeepLast: 3,
+			},
+			checker: NotNil,
+		},
+	} {
+		err := RetentionPolicy(&crv1alpha1.RetentionPolicy{Spec: tc.spec})
+		c.Check(err, tc.checker)
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+}
+
+func (s *ValidateSuite) TestBlueprintBinding(c *C) {
+	for _, tc := range []struct {
+		spec    *crv1alpha1.BlueprintBindingSpec
+		checker Checker
+	}{
+		{
+			spec:    nil,
+			checker: NotNil,
+		},
+		{
+			spec:    &crv1alpha1.BlueprintBindingSpec{Blueprint: "mysql-blueprint"},
+			checker: IsNil,
+		},
+		{
+			spec: &crv1alpha1.BlueprintBindingSpec{
+				Blueprint:   "mysql-blueprint",
+				Priority:    10,
+				Kinds:       []string{"statefulset"},
+				Namespaces:  []string{"mysql"},
+				Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"app": "mysql"}},
+				Annotations: map[string]string{"kanister.io/backup": "true"},
+			},
+			checker: IsNil,
+		},
+		{
+			spec:    &crv1alpha1.BlueprintBindingSpec{Kinds: []string{"statefulset"}},
+			checker: NotNil,
+		},
+		{
+			spec: &crv1alpha1.BlueprintBindingSpec{
+				Blueprint: "mysql-blueprint",
+				Selector:  &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}}},
+			},
+			checker: NotNil,
+		},
+	} {
+		err := BlueprintBinding(&crv1alpha1.BlueprintBinding{Spec: tc.spec})
+		c.Check(err, tc.checker)
+		if err != nil {
+			c.Check(IsError(err), Equals, true)
+		}
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
 }